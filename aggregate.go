@@ -0,0 +1,143 @@
+package log
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// aggregateInterval is how often an Aggregator writes its summary.
+const aggregateInterval = 10 * time.Second
+
+// aggregate is the summary of the values observed in one window.
+type aggregate struct {
+	count    int64
+	sum      float64
+	min, max float64
+}
+
+func (a aggregate) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt64("count", a.count)
+	enc.AddFloat64("min", a.min)
+	enc.AddFloat64("max", a.max)
+	enc.AddFloat64("avg", a.sum/float64(a.count))
+	return nil
+}
+
+// Aggregator summarizes high-frequency values, such as per-packet metrics,
+// into one entry per window instead of one entry per value. Create one with
+// Logger.Aggregate. An Aggregator is safe for concurrent use.
+type Aggregator struct {
+	logger  *Logger
+	traceId string
+	key     string
+
+	mu          sync.Mutex
+	window      aggregate
+	windowStart time.Time
+
+	done   chan struct{}
+	exited chan struct{}
+	once   sync.Once
+}
+
+// Aggregate returns an Aggregator that writes, every 10 seconds, an info entry
+// summarizing the values observed since the previous one as an object under
+// key, with the window's length in 'window_ms':
+//
+//	"message": "aggregated observations", "packet_bytes": {"count": 48210, "min": 64, "max": 1500, "avg": 512.4}, "window_ms": 10000
+//
+// Windows without observations write nothing. Call Stop when done to write
+// the last window and release the Aggregator's goroutine. Time is read from
+// Config.Clock.
+//
+// Example:
+//
+//	packets := logger.Aggregate("net-stats", "packet_bytes")
+//	defer packets.Stop()
+//	for packet := range incoming {
+//	    packets.Observe(float64(len(packet)))
+//	}
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID),
+// or if key is empty.
+func (l *Logger) Aggregate(traceId, key string) *Aggregator {
+	traceId = l.resolveTraceID(traceId)
+	if key == "" {
+		panic("log: aggregate key cannot be empty")
+	}
+
+	a := &Aggregator{
+		logger:      l,
+		traceId:     traceId,
+		key:         key,
+		windowStart: l.clock.Now(),
+		done:        make(chan struct{}),
+		exited:      make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *Aggregator) run() {
+	defer close(a.exited)
+
+	ticker := time.NewTicker(aggregateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.done:
+			return
+		case <-ticker.C:
+			a.Flush()
+		}
+	}
+}
+
+// Observe adds value to the current window.
+func (a *Aggregator) Observe(value float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.window.count == 0 {
+		a.window.min, a.window.max = value, value
+	} else {
+		a.window.min = min(a.window.min, value)
+		a.window.max = max(a.window.max, value)
+	}
+	a.window.count++
+	a.window.sum += value
+}
+
+// Flush writes the summary of the current window now, if it has any values,
+// and starts a new window.
+func (a *Aggregator) Flush() {
+	a.mu.Lock()
+	window := a.window
+	now := a.logger.clock.Now()
+	windowMs := now.Sub(a.windowStart).Milliseconds()
+	a.window, a.windowStart = aggregate{}, now
+	a.mu.Unlock()
+
+	if window.count == 0 {
+		return
+	}
+	a.logger.log(zapcore.InfoLevel, 1, time.Time{}, a.traceId, "aggregated observations", nil, []Field{
+		{zapField: zap.Object(a.key, window)},
+		Int64("window_ms", windowMs),
+	})
+}
+
+// Stop stops the periodic summaries and writes the last window. Values
+// observed afterwards are only written by an explicit Flush. Stop may be
+// called more than once.
+func (a *Aggregator) Stop() {
+	a.once.Do(func() {
+		close(a.done)
+		<-a.exited
+		a.Flush()
+	})
+}
@@ -0,0 +1,56 @@
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_Aggregate(t *testing.T) {
+	clock := &steppingClock{now: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC), step: 5 * time.Second}
+	logger, entries := newTestLogger(t, log.Config{Clock: clock})
+
+	packets := logger.Aggregate("net-stats", "packet_bytes")
+	for _, value := range []float64{512, 64, 1500, 512} {
+		packets.Observe(value)
+	}
+	packets.Flush()
+	packets.Flush() // Empty window
+	packets.Observe(100)
+	packets.Stop()
+	packets.Stop() // Safe to call twice
+
+	got := entries()
+	if len(got) != 2 {
+		t.Fatalf("expected a summary per non-empty window, got %v", got)
+	}
+	first := got[0]
+	if first["message"] != "aggregated observations" || first["trace_id"] != "net-stats" || first["level"] != "info" {
+		t.Errorf("unexpected summary entry %v", first)
+	}
+	want := map[string]any{"count": float64(4), "min": float64(64), "max": float64(1500), "avg": float64(647)}
+	summary, _ := first["packet_bytes"].(map[string]any)
+	for key, value := range want {
+		if summary[key] != value {
+			t.Errorf("expected %s=%v, got %v", key, value, summary[key])
+		}
+	}
+	if first["window_ms"] != float64(5000) {
+		t.Errorf("expected window_ms=5000, got %v", first["window_ms"])
+	}
+	if last, _ := got[1]["packet_bytes"].(map[string]any); last["count"] != float64(1) || last["avg"] != float64(100) {
+		t.Errorf("expected Stop to write the last window, got %v", got[1])
+	}
+}
+
+func TestLogger_AggregateEmptyKey(t *testing.T) {
+	logger, _ := newTestLogger(t, log.Config{})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for empty key, got none")
+		}
+	}()
+	logger.Aggregate("net-stats", "")
+}
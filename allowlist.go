@@ -0,0 +1,73 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// allowedField reports whether Config.AllowedFieldKeys lets f be written.
+// Fields that write nothing, such as MustLog, are always allowed, and inline
+// fields, such as Attempt, only if all of their keys are.
+func allowedField(f zap.Field, allowed map[string]bool) bool {
+	switch f.Type {
+	case zapcore.SkipType:
+		return true
+	case zapcore.InlineMarshalerType:
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		for key := range enc.Fields {
+			if !allowed[key] {
+				return false
+			}
+		}
+		return true
+	default:
+		return allowed[f.Key]
+	}
+}
+
+// filterMetadataKeys removes the top-level keys of metadata that
+// Config.AllowedFieldKeys does not list, returning the filtered metadata and
+// the number of keys removed. Metadata is inspected in its JSON form, so
+// struct fields follow their json tags. Metadata that is not a JSON object,
+// or that cannot be encoded, is returned unchanged.
+func filterMetadataKeys(metadata any, allowed map[string]bool) (any, int) {
+	var object map[string]any
+	switch m := metadata.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return metadata, 0
+	case map[string]any:
+		object = m
+	default:
+		encoded, err := json.Marshal(metadata)
+		if err != nil {
+			return metadata, 0 // serializeMetadata reports it
+		}
+		dec := json.NewDecoder(bytes.NewReader(encoded))
+		dec.UseNumber() // Keep large integers exact
+		if err := dec.Decode(&object); err != nil {
+			return metadata, 0 // Not an object
+		}
+	}
+
+	var dropped int
+	for key := range object {
+		if !allowed[key] {
+			dropped++
+		}
+	}
+	if dropped == 0 {
+		return metadata, 0
+	}
+
+	filtered := make(map[string]any, len(object)-dropped) // Never modify the caller's map
+	for key, value := range object {
+		if allowed[key] {
+			filtered[key] = value
+		}
+	}
+	return filtered, dropped
+}
@@ -0,0 +1,93 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestConfig_AllowedFieldKeys(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{
+		AllowedFieldKeys:    []string{"order_id", "attempt", "amount"},
+		PromoteMetadataKeys: []string{"amount", "card_number"},
+	})
+
+	bound := logger.With(log.String("order_id", "ord-1"), log.String("email", "a@example.com"))
+	bound.Info("req-123", "charged", map[string]any{"amount": 42, "card_number": "4111"},
+		log.String("ssn", "123-45-6789"),
+		log.Attempt(2, 0),
+		log.MustLog(),
+	)
+
+	got := entries()
+	if len(got) != 1 {
+		t.Fatalf("expected one entry, got %v", got)
+	}
+	logEntry := got[0]
+	for _, key := range []string{"email", "ssn", "meta_card_number"} {
+		if _, exists := logEntry[key]; exists {
+			t.Errorf("expected %s to be dropped, got %v", key, logEntry)
+		}
+	}
+	if logEntry["order_id"] != "ord-1" || logEntry["attempt"] != float64(2) || logEntry["meta_amount"] != float64(42) {
+		t.Errorf("expected allowed fields to be kept, got %v", logEntry)
+	}
+	metadata, _ := logEntry["metadata"].(map[string]any)
+	if len(metadata) != 1 || metadata["amount"] != float64(42) {
+		t.Errorf("expected only allowed metadata keys, got %v", logEntry["metadata"])
+	}
+	if dropped := logger.Stats().DisallowedFields; dropped != 3 {
+		t.Errorf("expected 3 disallowed fields counted (email, ssn, card_number), got %d", dropped)
+	}
+}
+
+func TestConfig_AllowedFieldKeysReserved(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{AllowedFieldKeys: []string{"order_id"}})
+
+	logger.Component("billing").Info("req-123", "charged", "plain metadata", log.String("trace_id", "spoofed"))
+
+	got := entries()
+	if len(got) != 1 {
+		t.Fatalf("expected one entry, got %v", got)
+	}
+	logEntry := got[0]
+	want := map[string]any{
+		"level":     "info",
+		"message":   "charged",
+		"service":   "test-service",
+		"env":       "dev",
+		"component": "billing",
+		"trace_id":  "req-123",
+		"metadata":  "plain metadata",
+	}
+	for key, value := range want {
+		if logEntry[key] != value {
+			t.Errorf("expected %s=%v written by the logger, got %v", key, value, logEntry[key])
+		}
+	}
+	if logger.Stats().DisallowedFields != 1 {
+		t.Errorf("expected the caller's trace_id field to be dropped, got %v", logger.Stats())
+	}
+}
+
+func TestConfig_AllowedFieldKeysStructMetadata(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{AllowedFieldKeys: []string{"amount"}})
+
+	type payment struct {
+		Amount     int    `json:"amount"`
+		CardNumber string `json:"card_number"`
+	}
+	logger.Info("req-123", "charged", payment{Amount: 42, CardNumber: "4111"})
+
+	got := entries()
+	if metadata, _ := got[0]["metadata"].(map[string]any); len(metadata) != 1 || metadata["amount"] != float64(42) {
+		t.Errorf("expected struct metadata filtered by json name, got %v", got[0]["metadata"])
+	}
+}
+
+func TestConfig_InvalidAllowedFieldKeys(t *testing.T) {
+	cfg := log.Config{Service: "test-service", Env: "dev", Level: log.InfoLevel, Output: log.OutputStdout, AllowedFieldKeys: []string{"order_id", " "}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for empty allowed field key, got nil")
+	}
+}
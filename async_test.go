@@ -0,0 +1,132 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_AsyncPreservesOrder(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{Async: true, AsyncWorkers: 4})
+
+	for i := 0; i < 500; i++ {
+		logger.Info("req-123", fmt.Sprintf("entry %d", i), nil)
+	}
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	got := entries()
+	if len(got) != 500 {
+		t.Fatalf("expected 500 entries after Sync, got %d", len(got))
+	}
+	for i, logEntry := range got {
+		if want := fmt.Sprintf("entry %d", i); logEntry["message"] != want {
+			t.Fatalf("entry %d: expected message %q, got %v", i, want, logEntry["message"])
+		}
+	}
+}
+
+func TestLogger_AsyncPerGoroutineOrder(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{Async: true, AsyncWorkers: 4, AsyncQueueSize: 16})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			child := logger.With(log.Int("goroutine", g))
+			for i := 0; i < 100; i++ {
+				child.Info("req-123", "entry", nil, log.Int("seq", i))
+			}
+		}()
+	}
+	wg.Wait()
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	next := map[float64]float64{}
+	got := entries()
+	if len(got) != 400 {
+		t.Fatalf("expected 400 entries after Close, got %d", len(got))
+	}
+	for _, logEntry := range got {
+		g := logEntry["goroutine"].(float64)
+		if logEntry["seq"] != next[g] {
+			t.Fatalf("goroutine %v: expected seq %v, got %v", g, next[g], logEntry["seq"])
+		}
+		next[g]++
+	}
+}
+
+func TestLogger_AsyncAfterClose(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{Async: true})
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	logger.Info("req-123", "after close", nil)
+
+	if got := entries(); len(got) != 1 {
+		t.Errorf("expected entry logged after Close to be written, got %d entries", len(got))
+	}
+}
+
+func TestLogger_DrainReturnsUnwrittenEntries(t *testing.T) {
+	var diagnostics bytes.Buffer
+
+	logger, err := log.New(log.Config{
+		Service:             "test-service",
+		Env:                 "dev",
+		Level:               log.InfoLevel,
+		Output:              log.OutputFile,
+		FilePath:            "async_test.go/unwritable.log", // parent is a file, so writes fail
+		Async:               true,
+		InternalErrorWriter: &diagnostics,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("req-1", "first", nil)
+	logger.Info("req-2", "second", nil)
+
+	unwritten, _ := logger.Drain()
+	if len(unwritten) != 2 {
+		t.Fatalf("expected 2 unwritten entries, got %d", len(unwritten))
+	}
+	var logEntry map[string]any
+	if err := json.Unmarshal(unwritten[1], &logEntry); err != nil {
+		t.Fatalf("unwritten entry is not valid JSON: %v", err)
+	}
+	if logEntry["message"] != "second" {
+		t.Errorf("expected message=second, got %v", logEntry["message"])
+	}
+	if !strings.Contains(diagnostics.String(), "write error") {
+		t.Errorf("expected write errors to be reported, got %q", diagnostics.String())
+	}
+
+	if again, _ := logger.Drain(); len(again) != 0 {
+		t.Errorf("expected entries to be returned only once, got %d", len(again))
+	}
+}
+
+func TestLogger_DrainSynchronous(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Info("req-123", "written", nil)
+
+	unwritten, err := logger.Drain()
+	if err != nil || len(unwritten) != 0 {
+		t.Errorf("expected no unwritten entries and no error, got %d, %v", len(unwritten), err)
+	}
+	if len(entries()) != 1 {
+		t.Error("expected the entry to have been written")
+	}
+}
@@ -0,0 +1,157 @@
+// Package batch gathers items, such as encoded log entries, and sends them in
+// batches. It is the batching shared by Config.BatchWindow and outputs that
+// live in their own modules, such as cloudwatch.
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Options configures a Batcher.
+type Options struct {
+	// Window is how long the first item of a batch waits for others before
+	// the batch is sent.
+	Window time.Duration
+
+	// MaxItems caps the items in one batch; reaching it sends the batch
+	// before Window elapses.
+	MaxItems int
+
+	// MaxBuffered bounds the items kept after a failed send, which are
+	// retried with the next batch. Beyond it the oldest are dropped. Zero
+	// keeps none: items whose send failed are dropped.
+	MaxBuffered int
+}
+
+// Batcher gathers items and sends them in batches: when Window has passed
+// since the first unsent item, when MaxItems are buffered, and on Flush and
+// Close. Sends are serialized and preserve the order items were added in.
+// Config.BatchWindow and outputs that ship entries over the network, such as
+// the cloudwatch package, build on it; it knows nothing about how a batch is
+// encoded or sent.
+type Batcher[T any] struct {
+	opts Options
+
+	// send sends batch and returns how many leading items were sent. Like
+	// io.Writer, it must return an error if that is fewer than len(batch).
+	send func(batch []T) (int, error)
+
+	mu      sync.Mutex // Guards the fields below
+	pending []T
+	timer   *time.Timer
+	err     error // From a background send, returned by the next Add or Flush
+	closed  bool
+
+	sendMu sync.Mutex // Serializes sends
+}
+
+// New creates a Batcher that sends batches with send.
+func New[T any](opts Options, send func(batch []T) (int, error)) *Batcher[T] {
+	return &Batcher[T]{opts: opts, send: send}
+}
+
+// Add buffers item, sending the batch if it is full. It returns the error of
+// that send or of the last background send, if any.
+func (b *Batcher[T]) Add(item T) error {
+	b.mu.Lock()
+	b.pending = append(b.pending, item)
+	full := len(b.pending) >= b.opts.MaxItems
+	if !full {
+		b.startWindow()
+	}
+	err := b.err
+	b.err = nil
+	b.mu.Unlock()
+
+	if full {
+		err = errors.Join(err, b.flush())
+	}
+	return err
+}
+
+// Flush sends all buffered items now.
+func (b *Batcher[T]) Flush() error {
+	b.mu.Lock()
+	err := b.err
+	b.err = nil
+	b.mu.Unlock()
+
+	return errors.Join(err, b.flush())
+}
+
+// Close stops sending on the window and sends all buffered items. Items
+// added after Close are sent by the next Flush, or when a batch fills up.
+func (b *Batcher[T]) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+
+	return b.Flush()
+}
+
+// startWindow starts the window of the current batch unless it is running
+// or the Batcher is closed. b.mu must be held.
+func (b *Batcher[T]) startWindow() {
+	if b.timer != nil || b.closed || len(b.pending) == 0 {
+		return
+	}
+	b.timer = time.AfterFunc(b.opts.Window, func() {
+		if err := b.flush(); err != nil {
+			b.mu.Lock()
+			b.err = err
+			b.mu.Unlock()
+		}
+	})
+}
+
+// flush sends the buffered items in batches of at most MaxItems. On failure
+// the unsent items are put back in front of the buffer, and retried when the
+// next window ends.
+func (b *Batcher[T]) flush() error {
+	b.sendMu.Lock()
+	defer b.sendMu.Unlock()
+
+	b.mu.Lock()
+	items := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+
+	for len(items) > 0 {
+		n, err := b.send(items[:min(len(items), b.opts.MaxItems)])
+		items = items[n:]
+		if err != nil {
+			return errors.Join(err, b.requeue(items))
+		}
+	}
+	return nil
+}
+
+// requeue puts unsent items back in front of the buffer, dropping the oldest
+// if the buffer would exceed MaxBuffered.
+func (b *Batcher[T]) requeue(items []T) error {
+	if b.opts.MaxBuffered <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending = append(items, b.pending...)
+	defer b.startWindow()
+	if dropped := len(b.pending) - b.opts.MaxBuffered; dropped > 0 {
+		b.pending = b.pending[dropped:]
+		return fmt.Errorf("batch buffer full, dropped %d oldest entries", dropped)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package batch_test
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glennprays/log/batch"
+)
+
+func TestBatcher(t *testing.T) {
+	var mu sync.Mutex
+	var sent [][]int
+	fail := true
+	b := batch.New(batch.Options{Window: time.Hour, MaxItems: 2, MaxBuffered: 10}, func(items []int) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			fail = false
+			return 0, errors.New("throttled")
+		}
+		sent = append(sent, slices.Clone(items))
+		return len(items), nil
+	})
+
+	if err := b.Add(1); err != nil {
+		t.Fatalf("expected the first item to be buffered, got %v", err)
+	}
+	if err := b.Add(2); err == nil {
+		t.Fatal("expected the failed send of a full batch to be returned")
+	}
+	if err := b.Add(3); err != nil {
+		t.Fatalf("expected the retried batch to be sent, got %v", err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := [][]int{{1, 2}, {3}}; !slices.EqualFunc(sent, want, slices.Equal) {
+		t.Errorf("expected batches %v in order, got %v", want, sent)
+	}
+}
@@ -0,0 +1,117 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func newBatchLogger(t *testing.T, cfg log.Config) (*log.Logger, chan []byte) {
+	t.Helper()
+	ch := make(chan []byte, 16)
+	cfg.Service = "test-service"
+	cfg.Env = "dev"
+	cfg.Level = log.InfoLevel
+	logger, err := log.NewChannelLogger(cfg, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	return logger, ch
+}
+
+// messages decodes a JSON array payload and returns its entries' messages.
+func messages(t *testing.T, payload []byte) []string {
+	t.Helper()
+	var entries []map[string]any
+	if err := json.Unmarshal(payload, &entries); err != nil {
+		t.Fatalf("payload is not a JSON array: %v: %q", err, payload)
+	}
+	var msgs []string
+	for _, entry := range entries {
+		msgs = append(msgs, entry["message"].(string))
+	}
+	return msgs
+}
+
+func TestConfig_BatchWindow(t *testing.T) {
+	logger, ch := newBatchLogger(t, log.Config{BatchWindow: time.Hour, MaxBatchEntries: 2})
+
+	logger.Info("req-123", "one", nil)
+	if len(ch) != 0 {
+		t.Fatal("expected entries to be held until the batch fills up")
+	}
+	logger.Info("req-123", "two", nil)
+	logger.Info("req-123", "three", nil)
+
+	payload := <-ch
+	if !bytes.HasSuffix(payload, []byte("]\n")) {
+		t.Errorf("expected payload to end with the line ending, got %q", payload)
+	}
+	if got := messages(t, payload); len(got) != 2 || got[0] != "one" || got[1] != "two" {
+		t.Errorf("expected a full batch [one two], got %v", got)
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if got := messages(t, <-ch); len(got) != 1 || got[0] != "three" {
+		t.Errorf("expected Close to write [three], got %v", got)
+	}
+}
+
+func TestConfig_BatchWindow_Elapses(t *testing.T) {
+	logger, ch := newBatchLogger(t, log.Config{BatchWindow: 10 * time.Millisecond})
+	defer logger.Close()
+
+	logger.Info("req-123", "one", nil)
+	logger.Info("req-123", "two", nil)
+
+	select {
+	case payload := <-ch:
+		if got := messages(t, payload); len(got) != 2 {
+			t.Errorf("expected both entries in one payload, got %v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the batch to be written when the window ended")
+	}
+}
+
+func TestConfig_BatchLines(t *testing.T) {
+	logger, ch := newBatchLogger(t, log.Config{BatchWindow: time.Hour, BatchLines: true})
+
+	logger.Info("req-123", "one", nil)
+	logger.Info("req-123", "two", nil)
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(<-ch), "\n"), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"message":"one"`) || !strings.Contains(lines[1], `"message":"two"`) {
+		t.Errorf("expected two newline-delimited entries, got %q", lines)
+	}
+}
+
+func TestConfig_BatchValidation(t *testing.T) {
+	cfg := log.Config{
+		Service:     "test-service",
+		Env:         "dev",
+		Level:       log.InfoLevel,
+		Output:      log.OutputStdout,
+		BatchWindow: -time.Second,
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative batch window, got nil")
+	}
+
+	cfg.BatchWindow = time.Second
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.MaxBatchEntries != 1000 {
+		t.Errorf("expected default max batch entries 1000, got %d", cfg.MaxBatchEntries)
+	}
+}
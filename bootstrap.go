@@ -0,0 +1,66 @@
+package log
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/glennprays/log/internal/zapimpl"
+)
+
+// bootstrapTraceID is the traceId of the entry reporting dropped bootstrap entries.
+const bootstrapTraceID = "log-bootstrap"
+
+// Bootstrap returns a Logger that keeps entries in memory, for code that runs
+// before the real logger exists, such as config loading or dependency wiring.
+// Pass the real logger the Bootstrap logger with AdoptBootstrap once it is
+// created. Entries of every level are kept, up to 10,000; later ones are
+// dropped. On Panic or Fatal before adoption, the kept entries are written to
+// stderr as JSON first, in case the process exits; after a recovered panic
+// they are still replayed on adoption.
+//
+// Example:
+//
+//	boot := log.Bootstrap()
+//	cfg, err := loadConfig(boot)
+//	logger, err := log.New(cfg.Log)
+//	logger.AdoptBootstrap(boot)
+func Bootstrap() *Logger {
+	core := zapimpl.NewBootstrapCore()
+	zapLogger := zap.New(core)
+	return &Logger{
+		zapLogger:      zapLogger,
+		pipeline:       &zapimpl.Pipeline{Logger: zapLogger},
+		stats:          &zapimpl.Stats{},
+		level:          zap.NewAtomicLevelAt(zapcore.DebugLevel),
+		clock:          systemClock{},
+		created:        time.Now(),
+		callerMinLevel: zapcore.DebugLevel,
+		fatalExitCode:  1,
+		bootstrap:      core,
+	}
+}
+
+// AdoptBootstrap writes the entries kept by a Bootstrap logger (and its child
+// loggers) through l, with their original timestamps, then makes them write
+// through l from now on, so components still holding them keep logging. l's
+// level, sampling, and bound fields apply. If entries were dropped because
+// the bootstrap buffer was full, a warning with 'dropped_entries' follows.
+//
+// Panics if boot was not created by Bootstrap or was already adopted.
+func (l *Logger) AdoptBootstrap(boot *Logger) {
+	if boot == nil || boot.bootstrap == nil {
+		panic("log: AdoptBootstrap requires a logger created by Bootstrap")
+	}
+	dropped, err := boot.bootstrap.Adopt(l.zapLogger.Core())
+	if err != nil {
+		panic("log: " + err.Error())
+	}
+	if dropped > 0 {
+		l.log(zapcore.WarnLevel, 1, time.Time{}, bootstrapTraceID, "bootstrap entries dropped", nil, []Field{
+			Int("dropped_entries", dropped),
+			Int("max_entries", zapimpl.MaxBootstrapEntries),
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package log_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_AdoptBootstrap(t *testing.T) {
+	boot := log.Bootstrap()
+	wiring := boot.With(log.String("phase", "wiring"))
+
+	boot.Debug("boot-1", "loading config", map[string]any{"path": "/etc/app.yaml"})
+	wiring.Info("boot-1", "connected to database", nil)
+	time.Sleep(5 * time.Millisecond)
+	adopted := time.Now()
+
+	logger, entries := newTestLogger(t, log.Config{Level: log.InfoLevel})
+	logger.AdoptBootstrap(boot)
+
+	got := entries()
+	if len(got) != 1 {
+		t.Fatalf("expected the info entry replayed and debug filtered by the real level, got %d: %v", len(got), got)
+	}
+	replayed := got[0]
+	if replayed["message"] != "connected to database" || replayed["phase"] != "wiring" ||
+		replayed["service"] != "test-service" || replayed["trace_id"] != "boot-1" {
+		t.Errorf("unexpected replayed entry %v", replayed)
+	}
+	ts, _ := time.Parse("2006-01-02T15:04:05.000Z0700", replayed["timestamp"].(string))
+	if !ts.Before(adopted.Add(-2 * time.Millisecond)) {
+		t.Errorf("expected the original timestamp, got %v", replayed["timestamp"])
+	}
+
+	// After adoption, the bootstrap logger writes through the real one
+	wiring.Info("boot-1", "late", nil)
+	wiring.Debug("boot-1", "filtered", nil)
+	if got := entries(); len(got) != 1 || got[0]["message"] != "late" || got[0]["phase"] != "wiring" {
+		t.Errorf("expected forwarded entry, got %v", got)
+	}
+}
+
+func TestLogger_AdoptBootstrap_Dropped(t *testing.T) {
+	boot := log.Bootstrap()
+	for range 10001 {
+		boot.Info("boot-1", "starting", nil)
+	}
+
+	logger, entries := newTestLogger(t, log.Config{Sampling: &log.SamplingConfig{Initial: 1}})
+	logger.AdoptBootstrap(boot)
+
+	got := entries()
+	last := got[len(got)-1]
+	if last["message"] != "bootstrap entries dropped" || last["dropped_entries"] != float64(1) {
+		t.Errorf("expected a dropped entries warning, got %v", last)
+	}
+}
+
+func TestLogger_AdoptBootstrap_RecoveredPanic(t *testing.T) {
+	stderr, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatalf("failed to create stderr file: %v", err)
+	}
+	defer stderr.Close()
+	saved := os.Stderr
+	os.Stderr = stderr
+	defer func() { os.Stderr = saved }()
+
+	boot := log.Bootstrap()
+	boot.Info("boot-1", "loading config", nil)
+	func() {
+		defer func() { recover() }()
+		boot.Panic("boot-1", "invalid config", nil)
+	}()
+	os.Stderr = saved
+
+	dump, _ := os.ReadFile(stderr.Name())
+	if !strings.Contains(string(dump), "loading config") || !strings.Contains(string(dump), "invalid config") {
+		t.Errorf("expected the buffered entries to be dumped to stderr, got %q", dump)
+	}
+
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.AdoptBootstrap(boot)
+	got := entries()
+	if len(got) != 2 || got[0]["message"] != "loading config" || got[1]["message"] != "invalid config" {
+		t.Errorf("expected the entries to be replayed after a recovered panic, got %v", got)
+	}
+}
+
+func TestLogger_AdoptBootstrap_Panics(t *testing.T) {
+	logger, _ := newTestLogger(t, log.Config{})
+	boot := log.Bootstrap()
+	logger.AdoptBootstrap(boot)
+
+	for name, adopt := range map[string]func(){
+		"not bootstrap":   func() { logger.AdoptBootstrap(logger) },
+		"nil":             func() { logger.AdoptBootstrap(nil) },
+		"already adopted": func() { logger.AdoptBootstrap(boot) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			adopt()
+		})
+	}
+}
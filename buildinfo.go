@@ -0,0 +1,39 @@
+package log
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// BuildCommit is the commit the binary was built from, added to entries as
+// 'commit' when Config.IncludeBuildInfo is true. Set it with a linker flag:
+//
+//	go build -ldflags "-X github.com/glennprays/log.BuildCommit=$(git rev-parse HEAD)"
+//
+// When empty, the VCS revision stamped by the Go toolchain is used if available.
+var BuildCommit string
+
+// buildInfoFields returns the 'commit' and 'go_version' fields.
+func buildInfoFields() []Field {
+	fields := []Field{String("go_version", runtime.Version())}
+	if commit := buildCommit(); commit != "" {
+		fields = append([]Field{String("commit", commit)}, fields...)
+	}
+	return fields
+}
+
+// buildCommit returns BuildCommit, falling back to the vcs.revision setting
+// from the binary's build info.
+func buildCommit() string {
+	if BuildCommit != "" {
+		return BuildCommit
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				return setting.Value
+			}
+		}
+	}
+	return ""
+}
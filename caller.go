@@ -15,7 +15,8 @@ type callerInfo struct {
 
 // getCaller extracts caller information from the call stack.
 // skip specifies the number of stack frames to skip (relative to getCaller itself).
-func getCaller(skip int) callerInfo {
+// trimPrefix is stripped from the file path when set, see Config.CallerTrimPrefix.
+func getCaller(skip int, trimPrefix string) callerInfo {
 	// Skip getCaller itself + additional frames requested by caller
 	pc, file, line, ok := runtime.Caller(skip + 1)
 	if !ok {
@@ -36,12 +37,22 @@ func getCaller(skip int) callerInfo {
 		}
 	}
 
-	// Simplify file path to just the filename
-	file = filepath.Base(file)
-
 	return callerInfo{
-		file:     file,
+		file:     trimCallerPath(file, trimPrefix),
 		line:     line,
 		function: funcName,
 	}
 }
+
+// trimCallerPath returns file relative to prefix when file is under it, and
+// just the filename otherwise. runtime.Caller always reports paths with
+// forward slashes, and prefix must end at a directory boundary.
+func trimCallerPath(file, prefix string) string {
+	prefix = strings.TrimSuffix(filepath.ToSlash(prefix), "/")
+	if prefix != "" {
+		if rel, ok := strings.CutPrefix(file, prefix+"/"); ok && rel != "" {
+			return rel
+		}
+	}
+	return filepath.Base(file)
+}
@@ -0,0 +1,28 @@
+package log
+
+// NewChannelLogger creates a Logger that sends each encoded entry to ch.
+// It is shorthand for New with Output set to OutputChannel and Channel set to ch.
+//
+// Each value received from ch is a complete JSON entry owned by the receiver.
+// If ch is full, the entry is dropped and counted in Stats.DroppedEntries,
+// unless cfg.BlockOnFullChannel is set, in which case the log call blocks.
+//
+// Example:
+//
+//	ch := make(chan []byte, 1024)
+//	logger, err := log.NewChannelLogger(log.Config{
+//	    Service: "my-service",
+//	    Env:     "production",
+//	    Level:   log.InfoLevel,
+//	}, ch)
+//
+//	go func() {
+//	    for entry := range ch {
+//	        process(entry)
+//	    }
+//	}()
+func NewChannelLogger(cfg Config, ch chan<- []byte) (*Logger, error) {
+	cfg.Output = OutputChannel
+	cfg.Channel = ch
+	return New(cfg)
+}
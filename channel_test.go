@@ -0,0 +1,101 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestNewChannelLogger_SendsEntries(t *testing.T) {
+	ch := make(chan []byte, 4)
+
+	logger, err := log.NewChannelLogger(log.Config{
+		Service: "test-service",
+		Env:     "dev",
+		Level:   log.InfoLevel,
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.Info("req-123", "first", nil)
+	logger.Info("req-456", "second", nil)
+
+	for _, want := range []string{"first", "second"} {
+		entry := <-ch
+		var logEntry map[string]any
+		if err := json.Unmarshal(bytes.TrimSpace(entry), &logEntry); err != nil {
+			t.Fatalf("channel entry is not valid JSON: %v", err)
+		}
+		if logEntry["message"] != want {
+			t.Errorf("expected message=%s, got %v", want, logEntry["message"])
+		}
+	}
+}
+
+func TestNewChannelLogger_DropsWhenFull(t *testing.T) {
+	ch := make(chan []byte, 1)
+
+	logger, err := log.NewChannelLogger(log.Config{
+		Service: "test-service",
+		Env:     "dev",
+		Level:   log.InfoLevel,
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.Info("req-123", "kept", nil)
+	logger.Info("req-123", "dropped", nil)
+	logger.With(log.String("child", "yes")).Info("req-123", "dropped", nil)
+
+	if got := logger.Stats().DroppedEntries; got != 2 {
+		t.Errorf("expected 2 dropped entries, got %d", got)
+	}
+	if len(ch) != 1 {
+		t.Errorf("expected 1 buffered entry, got %d", len(ch))
+	}
+}
+
+func TestNewChannelLogger_BlocksWhenFull(t *testing.T) {
+	ch := make(chan []byte, 1)
+
+	logger, err := log.NewChannelLogger(log.Config{
+		Service:            "test-service",
+		Env:                "dev",
+		Level:              log.InfoLevel,
+		BlockOnFullChannel: true,
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("req-123", "first", nil)
+		logger.Info("req-123", "second", nil)
+	}()
+
+	<-ch
+	<-ch
+	<-done
+
+	if got := logger.Stats().DroppedEntries; got != 0 {
+		t.Errorf("expected no dropped entries, got %d", got)
+	}
+}
+
+func TestNew_ChannelOutputWithoutChannel(t *testing.T) {
+	_, err := log.New(log.Config{
+		Service: "test-service",
+		Env:     "dev",
+		Level:   log.InfoLevel,
+		Output:  log.OutputChannel,
+	})
+	if err == nil {
+		t.Error("expected error for channel output without channel, got nil")
+	}
+}
@@ -0,0 +1,57 @@
+package log
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/glennprays/log/internal/zapimpl"
+)
+
+// ErrChecksumMismatch is returned by VerifyFile when a file's content does not
+// match its checksum line.
+var ErrChecksumMismatch = errors.New("log: checksum mismatch")
+
+// VerifyFile checks a log file written with Config.Checksum: the last line
+// must be a checksum line whose SHA-256 and entry count match the content
+// before it. Checksum lines of earlier sessions are not counted as entries. It returns an error wrapping ErrChecksumMismatch if the file was
+// modified, and another error if it cannot be read or has no checksum line,
+// for example because the logger was never closed.
+//
+// Example:
+//
+//	if err := log.VerifyFile("/var/log/archive/app.log"); err != nil {
+//	    return fmt.Errorf("archive integrity check failed: %w", err)
+//	}
+func VerifyFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	content, trailerLine := splitTrailer(data)
+	trailer, ok := zapimpl.ParseChecksumTrailer(trailerLine)
+	if !ok {
+		return fmt.Errorf("log: %s has no checksum line", path)
+	}
+
+	sum := sha256.Sum256(content)
+	if got := zapimpl.ChecksumPrefix + hex.EncodeToString(sum[:]); got != trailer.Checksum {
+		return fmt.Errorf("%w: %s content hashes to %s, checksum line has %s", ErrChecksumMismatch, path, got, trailer.Checksum)
+	}
+	if got := zapimpl.CountEntries(content); got != trailer.Entries {
+		return fmt.Errorf("%w: %s has %d entries, checksum line has %d", ErrChecksumMismatch, path, got, trailer.Entries)
+	}
+	return nil
+}
+
+// splitTrailer splits data into the content before its last line and the
+// last line, without its line ending.
+func splitTrailer(data []byte) (content, trailer []byte) {
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	i := bytes.LastIndexByte(data, '\n')
+	return data[:i+1], data[i+1:]
+}
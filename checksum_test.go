@@ -0,0 +1,82 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func newChecksumLogger(t *testing.T, path string) *log.Logger {
+	t.Helper()
+	logger, err := log.New(log.Config{
+		Service:  "test-service",
+		Env:      "dev",
+		Level:    log.InfoLevel,
+		Output:   log.OutputFile,
+		FilePath: path,
+		Checksum: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	return logger
+}
+
+func TestVerifyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	logger := newChecksumLogger(t, path)
+	logger.Info("req-1", "first", nil)
+	logger.Info("req-2", "second", nil)
+	if err := log.VerifyFile(path); err == nil {
+		t.Error("expected an error before Close writes the checksum line, got nil")
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if err := log.VerifyFile(path); err != nil {
+		t.Fatalf("expected the file to verify, got %v", err)
+	}
+
+	// Appending in a new session covers the old content too
+	logger = newChecksumLogger(t, path)
+	logger.Info("req-3", "third", nil)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if err := log.VerifyFile(path); err != nil {
+		t.Fatalf("expected the appended file to verify, got %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !bytes.HasSuffix(content, []byte(`"entries":3}`+"\n")) {
+		t.Errorf("expected the last checksum line to count 3 entries, got %s", content)
+	}
+
+	tampered := bytes.Replace(content, []byte("second"), []byte("SECOND"), 1)
+	if err := os.WriteFile(path, tampered, 0o644); err != nil {
+		t.Fatalf("failed to write log file: %v", err)
+	}
+	if err := log.VerifyFile(path); !errors.Is(err, log.ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch for a modified file, got %v", err)
+	}
+}
+
+func TestConfig_ChecksumRequiresFile(t *testing.T) {
+	cfg := log.Config{Service: "test-service", Env: "dev", Level: log.InfoLevel, Output: log.OutputStdout, Checksum: true}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for checksum without file output, got nil")
+	}
+
+	cfg = log.Config{Service: "test-service", Env: "dev", Level: log.InfoLevel, Output: log.OutputFile, FilePath: "app.log", Checksum: true, LineEnding: log.LineEndingNone}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for checksum without a line ending, got nil")
+	}
+}
@@ -0,0 +1,16 @@
+package log
+
+import "time"
+
+// Clock is the source of entry timestamps. Set Config.Clock to a fake clock in
+// tests for deterministic timestamps, or to an event-time clock in replay tools.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the default Clock, backed by time.Now.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,239 @@
+// Package cloudwatch provides a log output that ships entries to Amazon
+// CloudWatch Logs. It is a module of its own so that only services that use it
+// depend on the AWS SDK.
+package cloudwatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/glennprays/log"
+	"github.com/glennprays/log/batch"
+)
+
+// Output is the name under which Register adds the CloudWatch output.
+// Set Config.Output to it after calling Register.
+const Output log.OutputType = "cloudwatch"
+
+const (
+	// PutLogEvents limits: events per call, and bytes per call counting each
+	// message plus a fixed per-event overhead.
+	maxBatchEvents = 10000
+	maxBatchBytes  = 1048576
+	eventOverhead  = 26
+)
+
+// Client is the subset of the CloudWatch Logs API used by Writer.
+// *cloudwatchlogs.Client satisfies it.
+type Client interface {
+	PutLogEvents(ctx context.Context, params *cloudwatchlogs.PutLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error)
+}
+
+// Options configures a Writer.
+type Options struct {
+	// LogGroup is the CloudWatch log group (required). It must already exist.
+	LogGroup string
+
+	// LogStream is the log stream within LogGroup (required). It must already exist.
+	LogStream string
+
+	// BatchSize is the number of buffered entries that triggers a flush
+	// before FlushInterval elapses (default and maximum: 10000).
+	BatchSize int
+
+	// FlushInterval is how long a buffered entry waits for others before
+	// its batch is sent (default: 5s).
+	FlushInterval time.Duration
+
+	// MaxBufferedEvents bounds the entries kept while CloudWatch is failing or
+	// throttling. When it is exceeded the oldest entries are dropped
+	// (default: 100000).
+	MaxBufferedEvents int
+
+	// RequestTimeout bounds each PutLogEvents call, including SDK retries
+	// (default: 30s).
+	RequestTimeout time.Duration
+}
+
+// validate checks required options and sets defaults.
+func (o *Options) validate() error {
+	var errs []error
+	if strings.TrimSpace(o.LogGroup) == "" {
+		errs = append(errs, errors.New("log group is required"))
+	}
+	if strings.TrimSpace(o.LogStream) == "" {
+		errs = append(errs, errors.New("log stream is required"))
+	}
+
+	if o.BatchSize <= 0 || o.BatchSize > maxBatchEvents {
+		o.BatchSize = maxBatchEvents
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = 5 * time.Second
+	}
+	if o.MaxBufferedEvents <= 0 {
+		o.MaxBufferedEvents = 100000
+	}
+	if o.RequestTimeout <= 0 {
+		o.RequestTimeout = 30 * time.Second
+	}
+
+	return errors.Join(errs...)
+}
+
+// Register adds the CloudWatch output under the name Output. Each logger
+// created with Config.Output set to Output gets its own Writer for client
+// and opts, which Logger.Close flushes and stops.
+//
+// Register panics if called more than once.
+//
+// Example:
+//
+//	awsCfg, err := config.LoadDefaultConfig(ctx)
+//	// ...
+//	cloudwatch.Register(cloudwatchlogs.NewFromConfig(awsCfg), cloudwatch.Options{
+//	    LogGroup:  "/ecs/payments",
+//	    LogStream: taskID,
+//	})
+//
+//	logger, err := log.New(log.Config{
+//	    Service: "payments",
+//	    Env:     "production",
+//	    Level:   log.InfoLevel,
+//	    Output:  cloudwatch.Output,
+//	})
+//	defer logger.Close()
+func Register(client Client, opts Options) {
+	log.RegisterOutput(string(Output), func(cfg log.Config) (log.WriteSyncer, error) {
+		return NewWriter(client, opts)
+	})
+}
+
+// Writer batches entries and sends them to CloudWatch Logs with PutLogEvents.
+// Entries are sent when FlushInterval has passed since the first unsent
+// entry, when BatchSize entries are buffered, and on Sync and Close. Each
+// entry becomes one log event timestamped when it was written to the Writer.
+//
+// Entries that cannot be sent, for example because the request is throttled
+// after the SDK's own retries, are kept and retried with the next batch. The
+// error is returned from the next Write or Sync, so a logger reports it to
+// its InternalErrorWriter.
+type Writer struct {
+	client  Client
+	opts    Options
+	batcher *batch.Batcher[types.InputLogEvent]
+
+	// Only used while sending, which the batcher serializes
+	lastTimestamp int64
+	sequenceToken *string
+}
+
+// NewWriter creates a Writer. Call Close to flush remaining entries.
+func NewWriter(client Client, opts Options) (*Writer, error) {
+	if client == nil {
+		return nil, errors.New("cloudwatch: client is required")
+	}
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf("cloudwatch: invalid options: %w", err)
+	}
+
+	w := &Writer{client: client, opts: opts}
+	w.batcher = batch.New(batch.Options{
+		Window:      opts.FlushInterval,
+		MaxItems:    opts.BatchSize,
+		MaxBuffered: opts.MaxBufferedEvents,
+	}, w.send)
+	return w, nil
+}
+
+// Write buffers one entry. The trailing line ending is removed, since each
+// CloudWatch event is already a separate record.
+func (w *Writer) Write(p []byte) (int, error) {
+	message := strings.TrimRight(string(p), "\r\n")
+
+	timestamp := time.Now().UnixMilli()
+	err := w.batcher.Add(types.InputLogEvent{Message: &message, Timestamp: &timestamp})
+	return len(p), err
+}
+
+// Sync sends all buffered entries.
+func (w *Writer) Sync() error {
+	return w.batcher.Flush()
+}
+
+// Close sends all buffered entries and stops the background flush.
+// Writes after Close are buffered until the next Sync.
+func (w *Writer) Close() error {
+	return w.batcher.Close()
+}
+
+// send sends a batch in calls within the PutLogEvents limits, returning how
+// many leading events were sent.
+func (w *Writer) send(events []types.InputLogEvent) (int, error) {
+	// CloudWatch requires events in chronological order within a batch
+	for _, event := range events {
+		*event.Timestamp = max(*event.Timestamp, w.lastTimestamp)
+		w.lastTimestamp = *event.Timestamp
+	}
+
+	sent := 0
+	for sent < len(events) {
+		n := batchLen(events[sent:], w.opts.BatchSize)
+		if err := w.put(events[sent : sent+n]); err != nil {
+			return sent, err
+		}
+		sent += n
+	}
+	return sent, nil
+}
+
+// put sends one batch, following the sequence token protocol used by older
+// log streams: the token from each response is passed with the next call.
+func (w *Writer) put(events []types.InputLogEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.opts.RequestTimeout)
+	defer cancel()
+
+	for retried := false; ; retried = true {
+		out, err := w.client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+			LogGroupName:  &w.opts.LogGroup,
+			LogStreamName: &w.opts.LogStream,
+			LogEvents:     events,
+			SequenceToken: w.sequenceToken,
+		})
+		if err == nil {
+			w.sequenceToken = out.NextSequenceToken
+			return nil
+		}
+
+		var invalidToken *types.InvalidSequenceTokenException
+		if errors.As(err, &invalidToken) && !retried {
+			w.sequenceToken = invalidToken.ExpectedSequenceToken
+			continue
+		}
+
+		var alreadyAccepted *types.DataAlreadyAcceptedException
+		if errors.As(err, &alreadyAccepted) {
+			w.sequenceToken = alreadyAccepted.ExpectedSequenceToken
+			return nil
+		}
+
+		return fmt.Errorf("cloudwatch: put log events: %w", err)
+	}
+}
+
+// batchLen returns how many leading events fit in one PutLogEvents call.
+func batchLen(events []types.InputLogEvent, maxEvents int) int {
+	size := 0
+	for i, event := range events {
+		size += len(*event.Message) + eventOverhead
+		if i == maxEvents || (i > 0 && size > maxBatchBytes) {
+			return i
+		}
+	}
+	return len(events)
+}
@@ -0,0 +1,192 @@
+package cloudwatch_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/glennprays/log"
+	"github.com/glennprays/log/cloudwatch"
+)
+
+// fakeClient records PutLogEvents calls and fails with queued errors first.
+type fakeClient struct {
+	mu      sync.Mutex
+	calls   []*cloudwatchlogs.PutLogEventsInput
+	errs    []error
+	counter int
+}
+
+func (c *fakeClient) PutLogEvents(ctx context.Context, params *cloudwatchlogs.PutLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = append(c.calls, params)
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		return nil, err
+	}
+	c.counter++
+	token := fmt.Sprintf("token-%d", c.counter)
+	return &cloudwatchlogs.PutLogEventsOutput{NextSequenceToken: &token}, nil
+}
+
+// batches returns the messages of every call, in order.
+func (c *fakeClient) batches() [][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var batches [][]string
+	for _, call := range c.calls {
+		var batch []string
+		for _, event := range call.LogEvents {
+			batch = append(batch, *event.Message)
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+func newWriter(t *testing.T, client *fakeClient, opts cloudwatch.Options) *cloudwatch.Writer {
+	t.Helper()
+	opts.LogGroup = "/test/group"
+	opts.LogStream = "stream"
+	if opts.FlushInterval == 0 {
+		opts.FlushInterval = time.Hour // Flush explicitly in tests
+	}
+	w, err := cloudwatch.NewWriter(client, opts)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+func TestWriter_BatchesEntries(t *testing.T) {
+	client := &fakeClient{}
+	w := newWriter(t, client, cloudwatch.Options{BatchSize: 2})
+
+	for _, entry := range []string{"one\n", "two\n", "three\n"} {
+		if _, err := w.Write([]byte(entry)); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+	if got := client.batches(); len(got) != 1 || len(got[0]) != 2 || got[0][0] != "one" || got[0][1] != "two" {
+		t.Fatalf("expected a full batch [one two] without trailing newlines, got %v", got)
+	}
+
+	if err := w.Sync(); err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	if got := client.batches(); len(got) != 2 || got[1][0] != "three" {
+		t.Errorf("expected Sync to send [three], got %v", got)
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.calls[0].SequenceToken != nil || client.calls[1].SequenceToken == nil {
+		t.Error("expected the sequence token from the first response to be sent with the second call")
+	}
+	if *client.calls[0].LogGroupName != "/test/group" || *client.calls[0].LogStreamName != "stream" {
+		t.Errorf("unexpected destination %s/%s", *client.calls[0].LogGroupName, *client.calls[0].LogStreamName)
+	}
+}
+
+func TestWriter_FlushInterval(t *testing.T) {
+	client := &fakeClient{}
+	w := newWriter(t, client, cloudwatch.Options{FlushInterval: 10 * time.Millisecond})
+
+	w.Write([]byte("tick\n"))
+
+	deadline := time.Now().Add(time.Second)
+	for len(client.batches()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected background flush within FlushInterval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWriter_InvalidSequenceToken(t *testing.T) {
+	expected := "expected-token"
+	client := &fakeClient{errs: []error{&types.InvalidSequenceTokenException{ExpectedSequenceToken: &expected}}}
+	w := newWriter(t, client, cloudwatch.Options{})
+
+	w.Write([]byte("entry\n"))
+	if err := w.Sync(); err != nil {
+		t.Fatalf("expected retry with the expected token to succeed, got %v", err)
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if len(client.calls) != 2 || client.calls[1].SequenceToken == nil || *client.calls[1].SequenceToken != expected {
+		t.Errorf("expected a retry with token %q, got %d calls", expected, len(client.calls))
+	}
+}
+
+func TestWriter_KeepsEntriesWhenThrottled(t *testing.T) {
+	client := &fakeClient{errs: []error{&types.ThrottlingException{}}}
+	w := newWriter(t, client, cloudwatch.Options{})
+
+	w.Write([]byte("first\n"))
+	var throttled *types.ThrottlingException
+	if err := w.Sync(); !errors.As(err, &throttled) {
+		t.Fatalf("expected throttling error, got %v", err)
+	}
+
+	w.Write([]byte("second\n"))
+	if err := w.Sync(); err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	got := client.batches()
+	if last := got[len(got)-1]; len(last) != 2 || last[0] != "first" || last[1] != "second" {
+		t.Errorf("expected throttled entry to be resent in order, got %v", last)
+	}
+}
+
+func TestNewWriter_Validation(t *testing.T) {
+	if _, err := cloudwatch.NewWriter(&fakeClient{}, cloudwatch.Options{}); err == nil {
+		t.Error("expected error for missing log group and stream, got nil")
+	}
+	if _, err := cloudwatch.NewWriter(nil, cloudwatch.Options{LogGroup: "g", LogStream: "s"}); err == nil {
+		t.Error("expected error for nil client, got nil")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	client := &fakeClient{}
+	cloudwatch.Register(client, cloudwatch.Options{LogGroup: "/ecs/payments", LogStream: "task-1"})
+
+	logger, err := log.New(log.Config{
+		Service: "payments",
+		Env:     "production",
+		Level:   log.InfoLevel,
+		Output:  cloudwatch.Output,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("req-123", "charged", nil)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	got := client.batches()
+	if len(got) != 1 || len(got[0]) != 1 {
+		t.Fatalf("expected one event, got %v", got)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(got[0][0]), &entry); err != nil {
+		t.Fatalf("event is not valid JSON: %v", err)
+	}
+	if entry["message"] != "charged" || entry["service"] != "payments" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+}
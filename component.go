@@ -0,0 +1,25 @@
+package log
+
+// Component returns a child logger whose entries include a 'component' field
+// naming the part of the code that logged them. Components nest: calling
+// Component on a component logger appends the name with a dot, so
+//
+//	logger.Component("billing").Component("invoices")
+//
+// logs "component": "billing.invoices". Use Component instead of binding a
+// 'component' field with With, which would not nest.
+//
+// Panics if name is empty.
+func (l *Logger) Component(name string) *Logger {
+	if name == "" {
+		panic("log: component name cannot be empty")
+	}
+
+	child := *l
+	if l.component == "" {
+		child.component = name
+	} else {
+		child.component = l.component + "." + name
+	}
+	return &child
+}
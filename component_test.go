@@ -0,0 +1,38 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_Component(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	billing := logger.Component("billing")
+	billing.Info("req-1", "started", nil)
+	billing.Component("invoices").With(log.String("invoice_id", "inv-1")).Info("req-2", "sent", nil)
+	logger.Info("req-3", "root", nil)
+
+	got := entries()
+	if got[0]["component"] != "billing" {
+		t.Errorf("expected component=billing, got %v", got[0]["component"])
+	}
+	if got[1]["component"] != "billing.invoices" || got[1]["invoice_id"] != "inv-1" {
+		t.Errorf("expected nested component=billing.invoices with bound fields, got %v", got[1])
+	}
+	if _, exists := got[2]["component"]; exists {
+		t.Errorf("expected parent logger to have no component, got %v", got[2]["component"])
+	}
+}
+
+func TestLogger_ComponentEmptyName(t *testing.T) {
+	logger, _ := newTestLogger(t, log.Config{})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for empty component name, got none")
+		}
+	}()
+	logger.Component("")
+}
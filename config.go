@@ -1,9 +1,14 @@
 package log
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 )
 
 // Config holds logger configuration.
@@ -17,12 +22,87 @@ type Config struct {
 	Env string
 
 	// Level is the minimum log level (required).
-	// Use log.DebugLevel, log.InfoLevel, log.WarnLevel, log.ErrorLevel, or log.FatalLevel.
+	// Use log.DebugLevel, log.InfoLevel, log.WarnLevel, log.ErrorLevel, log.PanicLevel, or log.FatalLevel.
 	Level Level
 
-	// Output specifies where to write logs: OutputStdout or OutputFile (required).
+	// LevelFallback is used instead of Level when Level is set to an invalid
+	// value, so a typo in deployment config doesn't stop the service from
+	// starting. New reports the substitution to InternalErrorWriter.
+	// Default: "" (an invalid Level is a validation error)
+	LevelFallback Level
+
+	// Output specifies where to write logs: OutputStdout, OutputStderr,
+	// OutputFile, OutputChannel, or the name of an output added with
+	// RegisterOutput (required).
 	Output OutputType
 
+	// FallbackToStdout makes New write to stdout when the configured output
+	// cannot be opened, such as a FilePath in a missing or read-only directory or
+	// a registered output whose factory fails, instead of returning an error.
+	// New reports the failure to InternalErrorWriter.
+	// Default: false (New returns the error)
+	FallbackToStdout bool
+
+	// Format specifies how entries are encoded: FormatJSON, FormatCEF, FormatCSV,
+	// or FormatConsole.
+	// Default: FormatJSON
+	Format Format
+
+	// Schema selects the key layout of JSON entries for a specific consumer,
+	// e.g. SchemaDatadog. Only used when Format is FormatJSON.
+	// Default: SchemaDefault
+	Schema Schema
+
+	// Console additionally writes every entry to ConsoleWriter in a
+	// human-readable format (time, level, message, then the fields as JSON),
+	// for reading logs on a host while a collector consumes Output. Levels
+	// are colored when ConsoleWriter is a terminal.
+	// Default: false
+	Console bool
+
+	// ConsoleWriter receives console entries when Console is true.
+	// Default: os.Stderr
+	ConsoleWriter io.Writer
+
+	// Sinks are additional outputs, each with its own output and encoding,
+	// for dual-sink setups such as human-readable lines on stdout and JSON
+	// in a file. See Sink for what applies to them.
+	// Default: nil (only Output)
+	Sinks []Sink
+
+	// CEFVendor is the Device Vendor in CEF headers (default: Service).
+	// Only used when Format is FormatCEF.
+	CEFVendor string
+
+	// CEFVersion is the Device Version in CEF headers (default: "0").
+	// Only used when Format is FormatCEF.
+	CEFVersion string
+
+	// CSVColumns lists the CSV columns in order, by field key, such as
+	// "timestamp", "message", or "user_id". Fields without a column are
+	// dropped unless the list includes CSVOtherFields. Entries without a
+	// field get an empty cell. Only used when Format is FormatCSV.
+	// Default: DefaultCSVColumns
+	CSVColumns []string
+
+	// SortFields writes the fields of each entry in a fixed order: timestamp,
+	// level, message, service, env, trace_id, and metadata first, then all other
+	// fields alphabetically. zap otherwise writes fields in the order they were
+	// added, which varies with With chains. Useful for golden-file tests and
+	// diffing logs. Re-encoding each entry makes logging noticeably slower.
+	// Only used when Format is FormatJSON.
+	// Default: false
+	SortFields bool
+
+	// MessageFirst writes message, level, and timestamp as the first keys of
+	// each entry, in that order, for streaming parsers that stop reading after
+	// the leading keys. Without it, entries start with level, timestamp, and
+	// message, always ahead of all other fields. Re-encoding each entry makes
+	// logging noticeably slower.
+	// Only used when Format is FormatJSON.
+	// Default: false
+	MessageFirst bool
+
 	// FilePath is the path to the log file (required if Output is OutputFile).
 	FilePath string
 
@@ -38,16 +118,338 @@ type Config struct {
 	// Only used when Output is OutputFile.
 	MaxAgeDays int
 
+	// Checksum makes the file tamper-evident: on Close, a final line with the
+	// SHA-256 of all preceding content and the entry count is appended, which
+	// VerifyFile checks. The file is not rotated, so MaxSizeMB, MaxBackups, and
+	// MaxAgeDays are ignored. Only used when Output is OutputFile. Entries are
+	// counted by line, so it cannot be used with LineEndingNone.
+	// Default: false
+	Checksum bool
+
+	// EphemeralFile removes the file and its rotated backups when the logger
+	// is closed, for debug sessions that only need logs when something went
+	// wrong. The files are kept if a Panic or Fatal entry was logged, and
+	// remain if the process exits without calling Close. A Close deferred in
+	// main still runs while a panic unwinds, so such crashes only keep the
+	// files if they go through Logger.Panic or Fatal.
+	// Only used when Output is OutputFile.
+	// Default: false
+	EphemeralFile bool
+
+	// ColdDir enables a second, cold tier for rotated files: once a rotated
+	// file is older than HotAgeMinutes, it is compressed with gzip into ColdDir
+	// and removed from the log file's directory, which keeps the active file
+	// and recent backups uncompressed for fast grepping. MaxBackups limits the
+	// hot backups only: older ones move to ColdDir early instead of being
+	// deleted. MaxAgeDays applies to both tiers. Ages are measured
+	// with Clock, from the rotation time in the file name.
+	// Only used when Output is OutputFile.
+	// Default: "" (no cold tier)
+	ColdDir string
+
+	// HotAgeMinutes is how long rotated files stay hot before they move to
+	// ColdDir. Only used when ColdDir is set.
+	// Default: 0 (move as soon as rotated)
+	HotAgeMinutes int
+
+	// Channel receives a copy of each encoded entry, including the trailing newline
+	// (required if Output is OutputChannel).
+	Channel chan<- []byte
+
+	// BlockOnFullChannel makes log calls wait for room when Channel is full.
+	// When false, entries that do not fit are dropped and counted in Stats.DroppedEntries.
+	// Only used when Output is OutputChannel.
+	// Default: false (drop)
+	BlockOnFullChannel bool
+
+	// SchemaVersion, when set, is added to every entry as the 'schema_version' field.
+	// Bump it whenever the shape of your log entries changes so consumers can
+	// handle multiple format generations during a migration.
+	// Default: "" (field omitted)
+	SchemaVersion string
+
+	// Version is the version of the service, added to every entry as
+	// 'version' (or FieldNames.VersionKey) when set.
+	// Default: "" (field omitted)
+	Version string
+
+	// FieldNames renames the service, env, and version fields, so entries
+	// match the key names an existing pipeline indexes, such as
+	// 'service.name'. Only used when Format is FormatJSON.
+	// Default: the standard names
+	FieldNames FieldNames
+
+	// LargeNumbersAsString encodes Int, Int64, and Uint64 fields whose value is
+	// beyond ±2^53 as JSON strings instead of numbers. Consumers that parse JSON
+	// numbers as doubles (JavaScript, many log UIs) otherwise silently lose precision
+	// on large IDs. Only top-level fields are affected; values inside Any are not.
+	// Default: false
+	LargeNumbersAsString bool
+
+	// BoolsAsStrings encodes Bool fields as the JSON strings "true" and "false",
+	// for consumers that mishandle JSON booleans. Other types are unchanged.
+	// Only top-level fields are affected; values inside Any are not.
+	// Default: false
+	BoolsAsStrings bool
+
+	// OmitEmptyFields drops String fields with an empty or whitespace-only
+	// value and Any fields with a nil value, keeping entries compact when data
+	// is sparse. Zero numbers and false booleans are kept. Required fields
+	// such as trace_id and metadata are always written.
+	// Default: false
+	OmitEmptyFields bool
+
+	// AllowEmptyTraceID lets log calls pass an empty traceId without a
+	// default (see Logger.WithDefaultTraceID), for batch jobs that have no
+	// request to take one from. Their entries have no trace_id field. It
+	// applies to the logger and all of its children.
+	// Default: false (an empty traceId panics)
+	AllowEmptyTraceID bool
+
+	// Sampling, when set, limits how many entries with the same level and
+	// message are written per second. See SamplingConfig.
+	// Default: nil (every entry is written)
+	Sampling *SamplingConfig
+
+	// RepeatCooldown suppresses an entry when the same traceId logged the same
+	// message less than RepeatCooldown ago, such as a warning in a tight loop
+	// within one request. Other traces are unaffected. The next entry of the
+	// trace that is written reports the repeats suppressed since as
+	// 'repeated_message' and 'repeats_suppressed', with a separate report
+	// entry for each further message that had repeats; call Logger.EndTrace
+	// when a request ends to report repeats no later entry reported. Fatal
+	// entries are never suppressed. Suppressed entries are counted in
+	// Stats.SuppressedRepeats.
+	// Default: 0 (disabled)
+	RepeatCooldown time.Duration
+
+	// IncludeSampleDecision adds 'sampled': true to every entry written while
+	// Sampling or AdaptiveSampling is set, and logs at info level, every 10
+	// seconds and when the logger is closed, how many entries sampling dropped
+	// since the previous summary. Downstream, this tells a gap in the logs
+	// that sampling caused from one where nothing was logged. Only used when
+	// Sampling or AdaptiveSampling is set.
+	// Default: false
+	IncludeSampleDecision bool
+
+	// AdaptiveSampling, when set, drops a growing share of debug and info
+	// entries while the output is slow or the async queue is filling up, and
+	// returns to writing every entry once it recovers. See
+	// AdaptiveSamplingConfig for the control algorithm.
+	// Default: nil (disabled)
+	AdaptiveSampling *AdaptiveSamplingConfig
+
+	// MaxBytesPerSecond caps the bytes written to the output per second, to
+	// protect a metered log egress budget. Up to one second of budget can be
+	// used in a burst; entries beyond it are dropped, counted in Stats, and
+	// summarized in a warning every 10 seconds and when the logger is closed.
+	// Default: 0 (no limit)
+	MaxBytesPerSecond int
+
+	// Async moves encoding and writing off the calling goroutine. Entries are
+	// queued and encoded by a pool of AsyncWorkers goroutines, then written in
+	// the order they were logged. When the queue is full, log calls block.
+	// Call Sync or Close to wait for queued entries to be written.
+	// Fatal entries are always written before the process exits.
+	// Default: false
+	Async bool
+
+	// AsyncWorkers is the number of encoding goroutines (default: 2).
+	// Only used when Async is true.
+	AsyncWorkers int
+
+	// AsyncQueueSize is the maximum number of queued entries (default: 1024).
+	// Only used when Async is true.
+	AsyncQueueSize int
+
+	// LinePrefix and LineSuffix are written before and after each entry, on
+	// the same line, e.g. a stream marker for a log multiplexer. The suffix
+	// goes before the line ending. They apply to the structured output only,
+	// not the console mirror, and must not contain line breaks.
+	// Default: "" (none)
+	LinePrefix string
+	LineSuffix string
+
+	// LineEnding is written after each entry: LineEndingLF, LineEndingCRLF,
+	// or LineEndingNone for a writer that frames entries itself. It applies
+	// to the structured output only; the console mirror always uses "\n".
+	// Default: LineEndingLF
+	LineEnding string
+
+	// SerializeWrites wraps the output in a mutex so that only one entry is
+	// written at a time. zap writes each entry with a single Write call, so this
+	// is only needed for outputs whose Write is not safe for concurrent use or
+	// splits one call into several underlying writes, which can interleave
+	// entries. It serializes every log call on the output, which limits
+	// throughput under heavy concurrent logging.
+	// Default: false
+	SerializeWrites bool
+
+	// BatchWindow gathers the entries written within this long of the first
+	// one and writes them to the output as a single payload, for outputs that
+	// charge or slow down per write, such as HTTP endpoints. A payload is a
+	// JSON array of the entries, or with BatchLines the entries one after
+	// another, each with its line ending (a bulk body). CEF and CSV entries
+	// are always batched as lines. Entries are held in memory until written:
+	// Sync, Close, and Fatal write the pending batch.
+	// Default: 0 (every entry is written on its own)
+	BatchWindow time.Duration
+
+	// MaxBatchEntries caps the entries in one payload; a batch that reaches
+	// it is written before its window ends (default: 1000).
+	// Only used when BatchWindow is positive.
+	MaxBatchEntries int
+
+	// BatchLines writes batches as the entries one after another instead of
+	// as a JSON array. Only used when BatchWindow is positive.
+	// Default: false
+	BatchLines bool
+
+	// PromoteMetadataKeys lists metadata keys to copy to top-level fields named
+	// meta_<key>, so query tooling can index them. The full metadata is still
+	// logged under 'metadata'. Only map metadata with string keys is inspected.
+	// Default: nil (nothing promoted)
+	PromoteMetadataKeys []string
+
+	// AllowedFieldKeys, when set, lists the only keys fields and top-level
+	// metadata keys may have, for strict schema enforcement. Other fields,
+	// whether passed to a log call or bound with With, and other metadata keys
+	// are dropped and counted in Stats.DisallowedFields. The fields the logger
+	// writes itself, such as timestamp, level, message, service, env,
+	// trace_id, and metadata, are always written; a field passed by the caller
+	// with one of those keys is only kept if the key is listed. Metadata keys
+	// are checked on the metadata's JSON form, so struct fields follow their
+	// json tags; nested keys are not checked. PromoteMetadataKeys only promotes
+	// listed keys.
+	// Default: nil (any key)
+	AllowedFieldKeys []string
+
+	// FieldNamespace, when set, nests the fields passed to log calls and
+	// bound with With under an object with this key, so they cannot collide
+	// with the fields the logger writes itself or that an ingestion pipeline
+	// adds. Reserved fields such as timestamp, level, message, service, env,
+	// trace_id, and metadata stay at the top level. AllowedFieldKeys applies
+	// to the nested keys.
+	//
+	//	"trace_id": "req-123", "data": {"user_id": "user-456", "attempt": 2}
+	//
+	// Default: "" (fields are written at the top level)
+	FieldNamespace string
+
+	// EventTypes, when set, lists the event types Logger.Event accepts, so a
+	// misspelled type fails fast instead of creating a new dashboard group.
+	// Default: nil (any event type)
+	EventTypes []string
+
+	// MaxMetadataDepth limits how deeply nested metadata is logged. Maps,
+	// slices, and structs nested deeper than this many levels, counting the
+	// metadata itself as level 1, are replaced by {"_depth_truncated": true},
+	// which bounds entry size for deeply nested values.
+	// Default: 0 (no limit)
+	MaxMetadataDepth int
+
+	// MetadataSerializer, when set, encodes the 'metadata' value of every
+	// entry in place of the default JSON encoding, e.g. to write metadata as an
+	// escaped string. It receives metadata after log struct tags are applied,
+	// including nil, and must return valid JSON; an empty result writes null.
+	// An error or invalid JSON writes the unserializable-metadata placeholder.
+	// It must be safe for concurrent use.
+	// Default: nil (JSON object)
+	MetadataSerializer func(metadata any) (json.RawMessage, error)
+
+	// PreEmit, when set, is called for every entry that passes the level check,
+	// before it is encoded. It may read, modify, append to, or remove from the
+	// fields passed to the log call. It runs on the logging goroutine, so it
+	// must be fast and safe for concurrent use.
+	//
+	// Ordering: fields bound with With are already part of the logger and are
+	// not in fields. trace_id, metadata, promoted metadata keys, and
+	// caller/function are added after PreEmit returns, so it cannot change or
+	// remove them. Field settings such as OmitEmptyFields and
+	// LargeNumbersAsString apply to the fields PreEmit leaves.
+	// Default: nil
+	PreEmit func(level Level, msg string, fields *[]Field)
+
+	// InternalErrorWriter receives the logger's own diagnostics, such as failures
+	// to write an entry to the output. Keeping them out of the log stream means
+	// downstream parsers never see them mixed with structured entries.
+	// Default: os.Stderr
+	InternalErrorWriter io.Writer
+
+	// Clock provides the timestamp of entries logged without an explicit time.
+	// The At variants (InfoAt, ...) always use the time they are given.
+	// Default: the system clock
+	Clock Clock
+
+	// IncludeBuildInfo adds 'commit' (from BuildCommit) and 'go_version'
+	// (from runtime.Version) to every entry, so entries can be correlated with
+	// the build that produced them. 'commit' is omitted when it is unknown.
+	// Default: false
+	IncludeBuildInfo bool
+
+	// StacktraceLevel enables a 'stacktrace' field on entries at or above this level.
+	// Default: "" (disabled)
+	StacktraceLevel Level
+
+	// MaxStacktraceDepth limits captured stacktraces to this many frames. Longer
+	// stacks end with a "...truncated" line, which bounds entry size for deep
+	// recursion or deeply layered frameworks.
+	// Only used when StacktraceLevel is set.
+	// Default: 0 (no limit)
+	MaxStacktraceDepth int
+
+	// FatalExitCode is the process exit code used by Fatal after logging, so
+	// supervisors can tell fatal conditions apart from other failures. Use
+	// Logger.FatalCode to choose the code per call. Must be between 1 and 255.
+	// Default: 1
+	FatalExitCode int
+
+	// TimeUTC writes timestamps in UTC instead of the host's local time zone,
+	// so deployments in different regions emit comparable timestamps. It is
+	// recommended for all new services and may become the default in a
+	// future major version.
+	// Default: false (local time)
+	TimeUTC bool
+
+	// IncludeUptime adds an 'uptime_ms' field with the milliseconds since the
+	// logger was created by New. Child loggers share their parent's creation
+	// time, so uptime is consistent across a process.
+	// Default: false
+	IncludeUptime bool
+
+	// IncludeGoroutineID adds a 'goroutine_id' field with the ID of the
+	// goroutine that logged the entry, for correlating interleaved entries when
+	// debugging concurrency. IDs are reused after a goroutine exits, so they are
+	// not stable identifiers. Reading the ID costs a runtime.Stack call
+	// (a few microseconds) per entry, so enable it only while debugging.
+	// Default: false
+	IncludeGoroutineID bool
+
 	// EnableCaller enables automatic caller and function extraction for each log entry.
 	// When enabled, 'caller' (file:line) and 'function' fields are added to logs.
 	// Performance note: Uses runtime.Caller which has ~200-500ns overhead per log call.
 	// Recommended: Enable in dev/staging for debugging, disable in production for performance.
 	// Default: false (disabled)
 	EnableCaller bool
+
+	// CallerMinLevel limits caller and function fields to entries at or above
+	// this level, so high-volume debug and info entries skip the runtime.Caller
+	// cost. Only used when EnableCaller is true.
+	// Default: "" (all levels)
+	CallerMinLevel Level
+
+	// CallerTrimPrefix is stripped from the caller's file path, keeping a
+	// readable relative path such as 'internal/http/handler.go:42' instead of
+	// just the filename. Set it to the module root directory, or to the module
+	// path when building with -trimpath. Files outside the prefix fall back to
+	// the filename. Only used when EnableCaller is true.
+	// Default: "" (filename only)
+	CallerTrimPrefix string
 }
 
 // Validate checks if the Config is valid. Returns an error containing all validation failures.
-// It also sets default values for file rotation settings if they are not provided.
+// It also sets default values for format, file rotation, async, adaptive sampling, clock, and fatal exit code settings if they are not provided,
+// and replaces an invalid Level with LevelFallback when one is configured.
 func (c *Config) Validate() error {
 	var errs []error
 
@@ -64,24 +466,207 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.LevelFallback != "" {
+		if _, err := c.LevelFallback.toZapLevel(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid level fallback: %w", err))
+		}
+	}
+
 	if c.Level == "" {
 		errs = append(errs, errors.New("log level is required"))
 	} else {
 		if _, err := c.Level.toZapLevel(); err != nil {
-			errs = append(errs, err)
+			if _, fallbackErr := c.LevelFallback.toZapLevel(); c.LevelFallback != "" && fallbackErr == nil {
+				c.Level = c.LevelFallback
+			} else {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if c.StacktraceLevel != "" {
+		if _, err := c.StacktraceLevel.toZapLevel(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid stacktrace level: %w", err))
+		}
+	}
+
+	if c.CallerMinLevel != "" {
+		if _, err := c.CallerMinLevel.toZapLevel(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid caller min level: %w", err))
+		}
+	}
+
+	if c.RepeatCooldown < 0 {
+		errs = append(errs, fmt.Errorf("repeat cooldown must not be negative (got: %v)", c.RepeatCooldown))
+	}
+
+	if c.MaxMetadataDepth < 0 {
+		errs = append(errs, fmt.Errorf("max metadata depth must not be negative (got: %d)", c.MaxMetadataDepth))
+	}
+
+	if c.MaxStacktraceDepth < 0 {
+		errs = append(errs, fmt.Errorf("max stacktrace depth must not be negative (got: %d)", c.MaxStacktraceDepth))
+	}
+
+	if c.FatalExitCode < 0 || c.FatalExitCode > 255 {
+		errs = append(errs, fmt.Errorf("fatal exit code must be between 1 and 255 (got: %d)", c.FatalExitCode))
+	}
+
+	if c.Sampling != nil {
+		if c.Sampling.Initial <= 0 {
+			errs = append(errs, fmt.Errorf("sampling initial must be positive (got: %d)", c.Sampling.Initial))
+		}
+		if c.Sampling.Thereafter < 0 {
+			errs = append(errs, fmt.Errorf("sampling thereafter must not be negative (got: %d)", c.Sampling.Thereafter))
+		}
+	}
+
+	if c.BatchWindow < 0 {
+		errs = append(errs, fmt.Errorf("batch window must not be negative (got: %s)", c.BatchWindow))
+	}
+	if c.MaxBatchEntries < 0 {
+		errs = append(errs, fmt.Errorf("max batch entries must not be negative (got: %d)", c.MaxBatchEntries))
+	}
+
+	if c.MaxBytesPerSecond < 0 {
+		errs = append(errs, fmt.Errorf("max bytes per second must not be negative (got: %d)", c.MaxBytesPerSecond))
+	}
+
+	for _, key := range c.AllowedFieldKeys {
+		if strings.TrimSpace(key) == "" {
+			errs = append(errs, errors.New("allowed field keys must not be empty"))
+			break
+		}
+	}
+
+	if c.FieldNamespace != "" && (strings.TrimSpace(c.FieldNamespace) == "" || slices.Contains(reservedFieldKeys, c.FieldNamespace)) {
+		errs = append(errs, fmt.Errorf("field namespace must not be blank or reserved (got: %q)", c.FieldNamespace))
+	}
+
+	for _, eventType := range c.EventTypes {
+		if strings.TrimSpace(eventType) == "" {
+			errs = append(errs, errors.New("event types must not be empty"))
+			break
+		}
+	}
+
+	if c.AdaptiveSampling != nil {
+		adaptive := c.AdaptiveSampling
+		if adaptive.LatencyThreshold < 0 {
+			errs = append(errs, fmt.Errorf("adaptive sampling latency threshold must not be negative (got: %s)", adaptive.LatencyThreshold))
+		}
+		if adaptive.QueueThreshold < 0 || adaptive.QueueThreshold > 1 {
+			errs = append(errs, fmt.Errorf("adaptive sampling queue threshold must be between 0 and 1 (got: %g)", adaptive.QueueThreshold))
+		}
+		if adaptive.Interval < 0 {
+			errs = append(errs, fmt.Errorf("adaptive sampling interval must not be negative (got: %s)", adaptive.Interval))
+		}
+		if adaptive.MaxRatio < 0 {
+			errs = append(errs, fmt.Errorf("adaptive sampling max ratio must not be negative (got: %d)", adaptive.MaxRatio))
 		}
 	}
 
 	if c.Output == "" {
 		errs = append(errs, errors.New("output type is required"))
-	} else if c.Output != OutputStdout && c.Output != OutputFile {
-		errs = append(errs, fmt.Errorf("output must be stdout or file (got: %s)", c.Output))
+	} else if c.Output != OutputStdout && c.Output != OutputStderr && c.Output != OutputFile && c.Output != OutputChannel {
+		if _, ok := registeredOutput(c.Output); !ok {
+			errs = append(errs, fmt.Errorf("output must be stdout, stderr, file, channel, or a registered output (got: %s)", c.Output))
+		}
+	}
+
+	if c.Format == "" {
+		c.Format = FormatJSON
+	} else if c.Format != FormatJSON && c.Format != FormatCEF && c.Format != FormatCSV && c.Format != FormatConsole {
+		errs = append(errs, fmt.Errorf("format must be json, cef, csv, or console (got: %s)", c.Format))
+	}
+
+	for i := range c.Sinks {
+		if err := c.Sinks[i].validate(); err != nil {
+			errs = append(errs, fmt.Errorf("sink %d: %w", i, err))
+		}
+	}
+
+	if len(c.CSVColumns) > 0 {
+		if !c.usesFormat(FormatCSV) {
+			errs = append(errs, errors.New("csv columns require csv format"))
+		}
+		seen := make(map[string]bool, len(c.CSVColumns))
+		for _, column := range c.CSVColumns {
+			if strings.TrimSpace(column) == "" || seen[column] {
+				errs = append(errs, fmt.Errorf("csv columns must be non-empty and distinct (got: %q)", column))
+				break
+			}
+			seen[column] = true
+		}
+	} else if c.usesFormat(FormatCSV) {
+		c.CSVColumns = DefaultCSVColumns
+	}
+
+	switch c.LineEnding {
+	case "":
+		c.LineEnding = LineEndingLF
+	case LineEndingLF, LineEndingCRLF, LineEndingNone:
+	default:
+		errs = append(errs, fmt.Errorf("line ending must be \\n, \\r\\n, or none (got: %q)", c.LineEnding))
+	}
+
+	if strings.ContainsAny(c.LinePrefix, "\r\n") {
+		errs = append(errs, errors.New("line prefix must not contain line breaks"))
+	}
+	if strings.ContainsAny(c.LineSuffix, "\r\n") {
+		errs = append(errs, errors.New("line suffix must not contain line breaks"))
+	}
+
+	if c.Schema != SchemaDefault && c.Schema != SchemaDatadog {
+		errs = append(errs, fmt.Errorf("schema must be empty or datadog (got: %s)", c.Schema))
+	} else if c.Schema != SchemaDefault && c.Format != FormatJSON {
+		errs = append(errs, fmt.Errorf("schema %s requires json format", c.Schema))
+	}
+
+	if c.FieldNames != (FieldNames{}) {
+		if c.Format != FormatJSON {
+			errs = append(errs, errors.New("field names require json format"))
+		} else if err := c.validateFieldNames(); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	if c.Output == OutputFile && strings.TrimSpace(c.FilePath) == "" {
 		errs = append(errs, errors.New("file path is required when output is file"))
 	}
 
+	if c.Checksum && c.Output != OutputFile {
+		errs = append(errs, errors.New("checksum requires file output"))
+	}
+	if c.Checksum && c.LineEnding == LineEndingNone {
+		errs = append(errs, errors.New("checksum cannot be used with line ending none, which leaves entries uncounted"))
+	}
+
+	if c.EphemeralFile && c.Output != OutputFile {
+		errs = append(errs, errors.New("ephemeral file requires file output"))
+	}
+
+	if c.ColdDir != "" {
+		switch {
+		case c.Output != OutputFile:
+			errs = append(errs, errors.New("cold dir requires file output"))
+		case c.Checksum:
+			errs = append(errs, errors.New("cold dir cannot be used with checksum, which disables rotation"))
+		case c.EphemeralFile:
+			errs = append(errs, errors.New("cold dir cannot be used with ephemeral file, which only removes hot files"))
+		case samePath(c.ColdDir, filepath.Dir(c.FilePath)):
+			errs = append(errs, errors.New("cold dir must differ from the log file's directory"))
+		}
+	}
+
+	if c.HotAgeMinutes < 0 {
+		errs = append(errs, fmt.Errorf("hot age minutes must not be negative (got: %d)", c.HotAgeMinutes))
+	}
+
+	if c.Output == OutputChannel && c.Channel == nil {
+		errs = append(errs, errors.New("channel is required when output is channel"))
+	}
+
 	if c.MaxSizeMB <= 0 {
 		c.MaxSizeMB = 100
 	}
@@ -91,6 +676,43 @@ func (c *Config) Validate() error {
 	if c.MaxAgeDays <= 0 {
 		c.MaxAgeDays = 28
 	}
+	if c.CEFVendor == "" {
+		c.CEFVendor = c.Service
+	}
+	if c.CEFVersion == "" {
+		c.CEFVersion = "0"
+	}
+	if c.Clock == nil {
+		c.Clock = systemClock{}
+	}
+	if c.FatalExitCode == 0 {
+		c.FatalExitCode = 1
+	}
+	if c.AdaptiveSampling != nil {
+		adaptive := *c.AdaptiveSampling // Don't modify the caller's struct
+		if adaptive.LatencyThreshold <= 0 {
+			adaptive.LatencyThreshold = 10 * time.Millisecond
+		}
+		if adaptive.QueueThreshold <= 0 {
+			adaptive.QueueThreshold = 0.8
+		}
+		if adaptive.Interval <= 0 {
+			adaptive.Interval = time.Second
+		}
+		if adaptive.MaxRatio <= 0 {
+			adaptive.MaxRatio = 64
+		}
+		c.AdaptiveSampling = &adaptive
+	}
+	if c.AsyncWorkers <= 0 {
+		c.AsyncWorkers = 2
+	}
+	if c.AsyncQueueSize <= 0 {
+		c.AsyncQueueSize = 1024
+	}
+	if c.MaxBatchEntries == 0 {
+		c.MaxBatchEntries = 1000
+	}
 
 	if len(errs) > 0 {
 		return errors.Join(errs...)
@@ -98,3 +720,85 @@ func (c *Config) Validate() error {
 
 	return nil
 }
+
+// ApplyEnvDefaults fills in the recommended settings for cfg.Env in fields
+// that are left at their zero value. Explicitly set fields are never changed,
+// and an unknown Env changes nothing.
+//
+//	dev, development:  Level debug, Output stdout, Format console
+//	staging:           Level info,  Output stdout, Format json
+//	prod, production:  Level info,  Output stdout, Format json,
+//	                   Sampling 100 per second, then every 100th
+//
+// In dev, Format stays json when Schema or FieldNames is set, since they
+// require it.
+//
+// Example:
+//
+//	cfg := log.Config{Service: "my-service", Env: os.Getenv("APP_ENV")}
+//	log.ApplyEnvDefaults(&cfg)
+//	logger, err := log.New(cfg)
+func ApplyEnvDefaults(cfg *Config) {
+	var level Level
+	format := FormatJSON
+	var sampling *SamplingConfig
+	switch strings.ToLower(strings.TrimSpace(cfg.Env)) {
+	case "dev", "development":
+		level = DebugLevel
+		if cfg.Schema == SchemaDefault && cfg.FieldNames == (FieldNames{}) {
+			format = FormatConsole
+		}
+	case "staging":
+		level = InfoLevel
+	case "prod", "production":
+		level = InfoLevel
+		sampling = &SamplingConfig{Initial: 100, Thereafter: 100}
+	default:
+		return
+	}
+
+	if cfg.Level == "" {
+		cfg.Level = level
+	}
+	if cfg.Output == "" {
+		cfg.Output = OutputStdout
+	}
+	if cfg.Format == "" {
+		cfg.Format = format
+	}
+	if cfg.Sampling == nil {
+		cfg.Sampling = sampling
+	}
+}
+
+// usesFormat reports whether the output or a sink encodes entries with format.
+func (c *Config) usesFormat(format Format) bool {
+	return c.Format == format || slices.ContainsFunc(c.Sinks, func(sink Sink) bool {
+		return !sink.Console && sink.Format == format
+	})
+}
+
+// validateFieldNames checks that the service, env, and version keys are
+// distinct and don't replace the fields every entry already has.
+func (c *Config) validateFieldNames() error {
+	levelKey := "level"
+	if c.Schema == SchemaDatadog {
+		levelKey = "status"
+	}
+	used := map[string]bool{"timestamp": true, levelKey: true, "message": true, "trace_id": true, "metadata": true}
+	service, env, version := c.FieldNames.resolve(c.Schema)
+	for _, key := range []string{service, env, version} {
+		if strings.TrimSpace(key) == "" || used[key] {
+			return fmt.Errorf("field names must be distinct and not reserved (got: %q)", key)
+		}
+		used[key] = true
+	}
+	return nil
+}
+
+// samePath reports whether a and b name the same path once made absolute.
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -0,0 +1,413 @@
+package log_test
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func TestConfig_SchemaVersion(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{SchemaVersion: "2"})
+	logger.Info("req-123", "versioned", nil)
+	logger.With(log.String("user_id", "user-456")).Info("req-123", "child", nil)
+
+	for i, logEntry := range entries() {
+		if logEntry["schema_version"] != "2" {
+			t.Errorf("entry %d: expected schema_version=2, got %v", i, logEntry["schema_version"])
+		}
+	}
+}
+
+func TestConfig_SchemaVersionOmittedByDefault(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Info("req-123", "unversioned", nil)
+
+	if _, exists := entries()[0]["schema_version"]; exists {
+		t.Error("schema_version should not be present when SchemaVersion is empty")
+	}
+}
+
+func TestConfig_LargeNumbersAsString(t *testing.T) {
+	const largeID int64 = 9007199254740993 // 2^53 + 1, not representable as float64
+
+	logger, entries := newTestLogger(t, log.Config{LargeNumbersAsString: true})
+	logger.With(log.Int64("bound_id", largeID)).Info(
+		"req-123",
+		"large ids",
+		nil,
+		log.Int64("order_id", largeID),
+		log.Int64("negative_id", -largeID),
+		log.Uint64("account_id", 18446744073709551615),
+		log.Int64("small_id", 42),
+	)
+
+	// json.Unmarshal decodes numbers as float64, like a JavaScript parser would.
+	logEntry := entries()[0]
+	for key, want := range map[string]string{
+		"bound_id":    "9007199254740993",
+		"order_id":    "9007199254740993",
+		"negative_id": "-9007199254740993",
+		"account_id":  "18446744073709551615",
+	} {
+		if logEntry[key] != want {
+			t.Errorf("expected %s=%q, got %v", key, want, logEntry[key])
+		}
+	}
+	if logEntry["small_id"] != float64(42) {
+		t.Errorf("expected small_id to stay numeric, got %v", logEntry["small_id"])
+	}
+}
+
+func TestConfig_LargeNumbersAsNumbersByDefault(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Info("req-123", "large ids", nil, log.Int64("order_id", 9007199254740993))
+
+	if _, ok := entries()[0]["order_id"].(float64); !ok {
+		t.Error("order_id should be encoded as a number when LargeNumbersAsString is false")
+	}
+}
+
+func TestConfig_BoolsAsStrings(t *testing.T) {
+	ch := make(chan []byte, 1)
+	logger, err := log.NewChannelLogger(log.Config{
+		Service:        "test-service",
+		Env:            "dev",
+		Level:          log.InfoLevel,
+		BoolsAsStrings: true,
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.With(log.Bool("bound", false)).Info("req-123", "flags", nil,
+		log.Bool("cached", true),
+		log.Int("count", 3),
+		log.Float64("ratio", 0.5),
+		log.String("name", "true"),
+		log.Any("nested", map[string]bool{"ok": true}),
+	)
+
+	line := string(<-ch)
+	for _, want := range []string{
+		`"bound":"false"`,
+		`"cached":"true"`,
+		`"count":3`,
+		`"ratio":0.5`,
+		`"name":"true"`,
+		`"nested":{"ok":true}`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("expected %s in %s", want, line)
+		}
+	}
+}
+
+func TestConfig_InternalErrorWriter(t *testing.T) {
+	var diagnostics bytes.Buffer
+
+	logger, err := log.New(log.Config{
+		Service:             "test-service",
+		Env:                 "dev",
+		Level:               log.InfoLevel,
+		Output:              log.OutputFile,
+		FilePath:            "config_test.go/unwritable.log", // parent is a file, so writes fail
+		InternalErrorWriter: &diagnostics,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.Info("req-123", "cannot be written", nil)
+
+	if !strings.Contains(diagnostics.String(), "write error") {
+		t.Errorf("expected write error in internal error writer, got %q", diagnostics.String())
+	}
+}
+
+func TestConfig_SerializeWrites(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{SerializeWrites: true})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				logger.Info("req-123", "concurrent", nil, log.Int("goroutine", g))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// entries fails the test if any entry is not valid JSON.
+	if got := len(entries()); got != 400 {
+		t.Errorf("expected 400 entries, got %d", got)
+	}
+}
+
+func TestConfig_IncludeBuildInfo(t *testing.T) {
+	log.BuildCommit = "abc1234"
+	defer func() { log.BuildCommit = "" }()
+
+	logger, entries := newTestLogger(t, log.Config{IncludeBuildInfo: true})
+	logger.Info("req-123", "build info", nil)
+
+	logEntry := entries()[0]
+	if logEntry["commit"] != "abc1234" {
+		t.Errorf("expected commit=abc1234, got %v", logEntry["commit"])
+	}
+	if logEntry["go_version"] != runtime.Version() {
+		t.Errorf("expected go_version=%s, got %v", runtime.Version(), logEntry["go_version"])
+	}
+}
+
+func TestConfig_BuildInfoOmittedByDefault(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Info("req-123", "no build info", nil)
+
+	logEntry := entries()[0]
+	if _, exists := logEntry["go_version"]; exists {
+		t.Error("go_version should not be present when IncludeBuildInfo is false")
+	}
+}
+
+func TestConfig_LevelFallback(t *testing.T) {
+	var diagnostics bytes.Buffer
+
+	ch := make(chan []byte, 4)
+	logger, err := log.NewChannelLogger(log.Config{
+		Service:             "test-service",
+		Env:                 "production",
+		Level:               "verbose",
+		LevelFallback:       log.WarnLevel,
+		InternalErrorWriter: &diagnostics,
+	}, ch)
+	if err != nil {
+		t.Fatalf("expected fallback instead of error, got %v", err)
+	}
+
+	logger.Info("req-123", "filtered by fallback level", nil)
+	logger.Warn("req-123", "written", nil)
+	if len(ch) != 1 {
+		t.Errorf("expected only the warn entry to be written, got %d entries", len(ch))
+	}
+	if !strings.Contains(diagnostics.String(), `invalid log level "verbose", falling back to "warn"`) {
+		t.Errorf("expected fallback warning, got %q", diagnostics.String())
+	}
+}
+
+func TestConfig_LevelFallbackInvalid(t *testing.T) {
+	cfg := log.Config{
+		Service:       "test-service",
+		Env:           "production",
+		Level:         "verbose",
+		LevelFallback: "loud",
+		Output:        log.OutputStdout,
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid level and invalid fallback, got nil")
+	}
+}
+
+func TestApplyEnvDefaults(t *testing.T) {
+	tests := []struct {
+		env          string
+		wantLevel    log.Level
+		wantFormat   log.Format
+		wantSampling bool
+	}{
+		{env: "dev", wantLevel: log.DebugLevel, wantFormat: log.FormatConsole},
+		{env: "staging", wantLevel: log.InfoLevel, wantFormat: log.FormatJSON},
+		{env: "Production", wantLevel: log.InfoLevel, wantFormat: log.FormatJSON, wantSampling: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			cfg := log.Config{Service: "test-service", Env: tt.env}
+			log.ApplyEnvDefaults(&cfg)
+
+			if cfg.Level != tt.wantLevel || cfg.Output != log.OutputStdout || cfg.Format != tt.wantFormat {
+				t.Errorf("unexpected defaults: level=%s output=%s format=%s", cfg.Level, cfg.Output, cfg.Format)
+			}
+			if (cfg.Sampling != nil) != tt.wantSampling {
+				t.Errorf("expected sampling=%v, got %+v", tt.wantSampling, cfg.Sampling)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("expected defaults to be valid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestApplyEnvDefaults_KeepsExplicitFields(t *testing.T) {
+	sampling := &log.SamplingConfig{Initial: 10, Thereafter: 5}
+	cfg := log.Config{
+		Env:      "prod",
+		Level:    log.WarnLevel,
+		Output:   log.OutputFile,
+		Format:   log.FormatCEF,
+		Sampling: sampling,
+	}
+	log.ApplyEnvDefaults(&cfg)
+
+	if cfg.Level != log.WarnLevel || cfg.Output != log.OutputFile || cfg.Format != log.FormatCEF || cfg.Sampling != sampling {
+		t.Errorf("explicit fields were overridden: %+v", cfg)
+	}
+
+	datadog := log.Config{Env: "dev", Schema: log.SchemaDatadog}
+	log.ApplyEnvDefaults(&datadog)
+	if datadog.Format != log.FormatJSON {
+		t.Errorf("expected json in dev when the schema requires it, got %s", datadog.Format)
+	}
+
+	unknown := log.Config{Env: "qa"}
+	log.ApplyEnvDefaults(&unknown)
+	if unknown.Level != "" || unknown.Output != "" {
+		t.Errorf("expected unknown env to change nothing, got %+v", unknown)
+	}
+}
+
+func TestConfig_Sampling(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{
+		Sampling: &log.SamplingConfig{Initial: 2, Thereafter: 3},
+		Clock:    fixedClock{now: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)}, // One sampling window
+	})
+
+	for i := 0; i < 8; i++ {
+		logger.Info("req-123", "hot loop", nil, log.Int("i", i))
+	}
+	logger.Info("req-123", "other message", nil)
+
+	var got []any
+	for _, logEntry := range entries() {
+		got = append(got, logEntry["i"])
+	}
+	// Entries 0 and 1, then every 3rd: 4 and 7; a different message is counted separately
+	want := []any{float64(0), float64(1), float64(4), float64(7), nil}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d entries, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d: expected i=%v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestConfig_InvalidSampling(t *testing.T) {
+	cfg := log.Config{
+		Service:  "test-service",
+		Env:      "dev",
+		Level:    log.InfoLevel,
+		Output:   log.OutputStdout,
+		Sampling: &log.SamplingConfig{Initial: 0},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for non-positive sampling initial, got nil")
+	}
+}
+
+func TestConfig_FatalExitCode(t *testing.T) {
+	cfg := log.Config{Service: "test-service", Env: "dev", Level: log.InfoLevel, Output: log.OutputStdout}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.FatalExitCode != 1 {
+		t.Errorf("expected default fatal exit code 1, got %d", cfg.FatalExitCode)
+	}
+
+	cfg.FatalExitCode = 256
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for fatal exit code above 255, got nil")
+	}
+}
+
+func TestConfig_InvalidColdDir(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		cfg  log.Config
+	}{
+		{"not file output", log.Config{Output: log.OutputStdout, ColdDir: "/var/log/cold"}},
+		{"checksum", log.Config{Output: log.OutputFile, FilePath: "/var/log/app.log", Checksum: true, ColdDir: "/var/log/cold"}},
+		{"same directory", log.Config{Output: log.OutputFile, FilePath: "/var/log/app.log", ColdDir: "/var/log/"}},
+		{"same directory, relative", log.Config{Output: log.OutputFile, FilePath: "app.log", ColdDir: wd}},
+		{"negative hot age", log.Config{Output: log.OutputFile, FilePath: "/var/log/app.log", ColdDir: "/var/log/cold", HotAgeMinutes: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Service, tt.cfg.Env, tt.cfg.Level = "test-service", "dev", log.InfoLevel
+			if err := tt.cfg.Validate(); err == nil {
+				t.Error("expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestConfig_FieldNames(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{
+		Version: "1.4.2",
+		FieldNames: log.FieldNames{
+			ServiceKey: "service.name",
+			EnvKey:     "deployment.environment",
+			VersionKey: "service.version",
+		},
+	})
+	logger.Info("req-123", "renamed", nil)
+
+	logEntry := entries()[0]
+	for key, want := range map[string]string{
+		"service.name":           "test-service",
+		"deployment.environment": "dev",
+		"service.version":        "1.4.2",
+	} {
+		if logEntry[key] != want {
+			t.Errorf("expected %s=%q, got %v", key, want, logEntry[key])
+		}
+	}
+	for _, key := range []string{"service", "env", "version"} {
+		if _, exists := logEntry[key]; exists {
+			t.Errorf("expected no %s field after renaming, got %v", key, logEntry[key])
+		}
+	}
+}
+
+func TestConfig_Version(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{Version: "1.4.2"})
+	logger.Info("req-123", "versioned", nil)
+
+	logEntry := entries()[0]
+	if logEntry["version"] != "1.4.2" || logEntry["service"] != "test-service" {
+		t.Errorf("expected version and service fields, got %v", logEntry)
+	}
+}
+
+func TestConfig_InvalidFieldNames(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  log.Config
+	}{
+		{"reserved", log.Config{FieldNames: log.FieldNames{ServiceKey: "message"}}},
+		{"duplicate", log.Config{FieldNames: log.FieldNames{EnvKey: "service"}}},
+		{"datadog level", log.Config{Schema: log.SchemaDatadog, FieldNames: log.FieldNames{VersionKey: "status"}}},
+		{"blank", log.Config{FieldNames: log.FieldNames{ServiceKey: " "}}},
+		{"cef", log.Config{Format: log.FormatCEF, FieldNames: log.FieldNames{ServiceKey: "app"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Service, tt.cfg.Env, tt.cfg.Level, tt.cfg.Output = "test-service", "dev", log.InfoLevel, log.OutputStdout
+			if err := tt.cfg.Validate(); err == nil {
+				t.Error("expected validation error, got nil")
+			}
+		})
+	}
+}
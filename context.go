@@ -0,0 +1,116 @@
+package log
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// contextKey is the context key for the Logger stored by IntoContext.
+type contextKey struct{}
+
+// traceIDKey is the context key for the traceId stored by ContextWithTraceID.
+type traceIDKey struct{}
+
+// nopLogger is returned by FromContext when the context has no Logger.
+var nopLogger = NewNop()
+
+// IntoContext returns a copy of ctx carrying logger, typically a child logger
+// with request-scoped fields, for retrieval with FromContext.
+//
+// Example:
+//
+//	requestLogger := logger.With(log.String("user_id", userID))
+//	ctx = log.IntoContext(ctx, requestLogger)
+func IntoContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the Logger stored in ctx by IntoContext, or a Logger
+// that discards every entry if there is none, so callers never need a nil check.
+//
+// Example:
+//
+//	func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//	    log.FromContext(r.Context()).Info(traceID, "handling request", nil)
+//	}
+func FromContext(ctx context.Context) *Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*Logger); ok && logger != nil {
+		return logger
+	}
+	return nopLogger
+}
+
+// ContextWithTraceID returns a copy of ctx carrying the request's traceId,
+// for the logger returned by WithContext and the Ctx methods, such as
+// InfoCtx. Set it once, for example in middleware, instead of passing the
+// traceId through every helper.
+//
+// Example:
+//
+//	ctx := log.ContextWithTraceID(r.Context(), r.Header.Get("X-Request-ID"))
+//	logger.InfoCtx(ctx, "handling request", nil)
+func ContextWithTraceID(ctx context.Context, traceId string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceId)
+}
+
+// TraceIDFromContext returns the traceId stored in ctx by ContextWithTraceID,
+// or "" if there is none.
+func TraceIDFromContext(ctx context.Context) string {
+	traceId, _ := ctx.Value(traceIDKey{}).(string)
+	return traceId
+}
+
+// WithContext returns a child logger that uses the traceId stored in ctx by
+// ContextWithTraceID as its default (see WithDefaultTraceID), so its calls
+// can pass an empty traceId. With SchemaDatadog, it also binds the span
+// stored by ContextWithDatadogTrace. Without either in ctx, it returns l
+// unchanged.
+//
+// Example:
+//
+//	requestLogger := logger.WithContext(ctx)
+//	requestLogger.Info("", "loaded order", nil)
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	child := l
+	if traceId := TraceIDFromContext(ctx); traceId != "" {
+		child = child.WithDefaultTraceID(traceId)
+	}
+	if fields := l.withDatadogTrace(ctx, nil); len(fields) > 0 {
+		child = child.With(fields...)
+	}
+	return child
+}
+
+// DebugCtx is like Debug, taking the traceId from ctx (see ContextWithTraceID)
+// and, with SchemaDatadog, the Datadog span (see ContextWithDatadogTrace).
+//
+// Panics if ctx has no traceId and the logger has no default (WithDefaultTraceID).
+func (l *Logger) DebugCtx(ctx context.Context, msg string, metadata any, fields ...Field) {
+	l.log(zapcore.DebugLevel, 1, time.Time{}, TraceIDFromContext(ctx), msg, metadata, l.withDatadogTrace(ctx, fields))
+}
+
+// InfoCtx is like Info, taking the traceId from ctx (see ContextWithTraceID)
+// and, with SchemaDatadog, the Datadog span (see ContextWithDatadogTrace).
+//
+// Panics if ctx has no traceId and the logger has no default (WithDefaultTraceID).
+func (l *Logger) InfoCtx(ctx context.Context, msg string, metadata any, fields ...Field) {
+	l.log(zapcore.InfoLevel, 1, time.Time{}, TraceIDFromContext(ctx), msg, metadata, l.withDatadogTrace(ctx, fields))
+}
+
+// WarnCtx is like Warn, taking the traceId from ctx (see ContextWithTraceID)
+// and, with SchemaDatadog, the Datadog span (see ContextWithDatadogTrace).
+//
+// Panics if ctx has no traceId and the logger has no default (WithDefaultTraceID).
+func (l *Logger) WarnCtx(ctx context.Context, msg string, metadata any, fields ...Field) {
+	l.log(zapcore.WarnLevel, 1, time.Time{}, TraceIDFromContext(ctx), msg, metadata, l.withDatadogTrace(ctx, fields))
+}
+
+// ErrorCtx is like Error, taking the traceId from ctx (see ContextWithTraceID)
+// and, with SchemaDatadog, the Datadog span (see ContextWithDatadogTrace).
+//
+// Panics if ctx has no traceId and the logger has no default (WithDefaultTraceID).
+func (l *Logger) ErrorCtx(ctx context.Context, msg string, metadata any, fields ...Field) {
+	l.log(zapcore.ErrorLevel, 1, time.Time{}, TraceIDFromContext(ctx), msg, metadata, l.withDatadogTrace(ctx, fields))
+}
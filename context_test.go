@@ -0,0 +1,69 @@
+package log_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestContext_RoundTrip(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	requestLogger := logger.With(log.String("user_id", "user-456"))
+
+	ctx := log.IntoContext(context.Background(), requestLogger)
+	if got := log.FromContext(ctx); got != requestLogger {
+		t.Fatalf("expected the stored logger, got %p", got)
+	}
+
+	log.FromContext(ctx).Info("req-123", "from context", nil)
+	if got := entries(); len(got) != 1 || got[0]["user_id"] != "user-456" {
+		t.Errorf("expected an entry with the request fields, got %v", got)
+	}
+}
+
+func TestContext_MissingLogger(t *testing.T) {
+	logger := log.FromContext(context.Background())
+	if logger == nil {
+		t.Fatal("expected a nop logger, got nil")
+	}
+
+	// Discards everything without panicking, including on child loggers
+	logger.With(log.String("k", "v")).Component("c").Info("req-123", "discarded", nil)
+	if err := logger.Sync(); err != nil {
+		t.Errorf("Sync returned error: %v", err)
+	}
+
+	var nilLogger *log.Logger
+	if log.FromContext(log.IntoContext(context.Background(), nilLogger)) == nil {
+		t.Error("expected a nop logger for a stored nil logger, got nil")
+	}
+}
+
+func TestContext_TraceID(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	ctx := log.ContextWithTraceID(context.Background(), "req-123")
+
+	logger.InfoCtx(ctx, "from ctx", nil)
+	logger.WithContext(ctx).Warn("", "from child", nil)
+	logger.ErrorCtx(ctx, "error from ctx", nil)
+
+	for _, entry := range entries() {
+		if entry["trace_id"] != "req-123" {
+			t.Errorf("expected trace_id from context, got %v", entry)
+		}
+	}
+	if got := log.TraceIDFromContext(context.Background()); got != "" {
+		t.Errorf("expected no traceId, got %q", got)
+	}
+	if logger.WithContext(context.Background()) != logger {
+		t.Error("expected WithContext without a traceId to return the logger")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic without a traceId in the context")
+		}
+	}()
+	logger.InfoCtx(context.Background(), "no trace", nil)
+}
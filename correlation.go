@@ -0,0 +1,57 @@
+package log
+
+import "strings"
+
+// WithCorrelation returns a child logger whose entries include a
+// 'correlation_id' field, identifying a user journey that spans many traces.
+// Unlike binding the field with With, calling WithCorrelation again replaces
+// the ID instead of adding a second 'correlation_id' key.
+//
+// Example:
+//
+//	journeyLogger := logger.WithCorrelation(session.JourneyID)
+//	journeyLogger.Info(traceID, "checkout started", nil) // "correlation_id": "..."
+//
+// Panics if id is empty or only whitespace.
+func (l *Logger) WithCorrelation(id string) *Logger {
+	if strings.TrimSpace(id) == "" {
+		panic("log: correlation id cannot be empty")
+	}
+
+	child := *l
+	child.correlationID = id
+	return &child
+}
+
+// WithDefaultTraceID returns a child logger that uses id as the traceId of
+// calls that pass an empty one, instead of panicking. Use it for background
+// workers and scheduled jobs, which have no request to take a traceId from.
+// An explicit traceId still takes precedence.
+//
+// Example:
+//
+//	jobLogger := logger.WithDefaultTraceID("nightly-cleanup")
+//	jobLogger.Info("", "removed expired sessions", nil) // "trace_id": "nightly-cleanup"
+//
+// Panics if id is empty or only whitespace.
+func (l *Logger) WithDefaultTraceID(id string) *Logger {
+	if strings.TrimSpace(id) == "" {
+		panic("log: default traceId cannot be empty")
+	}
+
+	child := *l
+	child.traceID = id
+	return &child
+}
+
+// resolveTraceID returns traceId, or the logger's default if it is empty.
+// It panics if both are empty, unless Config.AllowEmptyTraceID is set.
+func (l *Logger) resolveTraceID(traceId string) string {
+	if traceId != "" {
+		return traceId
+	}
+	if l.traceID == "" && !l.allowEmptyTraceID {
+		panic("log: traceId cannot be empty")
+	}
+	return l.traceID
+}
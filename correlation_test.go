@@ -0,0 +1,107 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_WithCorrelation(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	journey := logger.WithCorrelation("journey-1")
+	journey.Info("req-1", "first request", nil)
+	journey.With(log.String("step", "pay")).Info("req-2", "second request", nil)
+	journey.WithCorrelation("journey-2").Info("req-3", "replaced", nil)
+	logger.Info("req-4", "no journey", nil)
+
+	got := entries()
+	if got[0]["correlation_id"] != "journey-1" || got[0]["trace_id"] != "req-1" {
+		t.Errorf("expected correlation_id alongside trace_id, got %v", got[0])
+	}
+	if got[1]["correlation_id"] != "journey-1" || got[1]["step"] != "pay" {
+		t.Errorf("expected correlation_id kept by child loggers, got %v", got[1])
+	}
+	if got[2]["correlation_id"] != "journey-2" {
+		t.Errorf("expected correlation_id replaced, got %v", got[2]["correlation_id"])
+	}
+	if _, exists := got[3]["correlation_id"]; exists {
+		t.Errorf("expected parent logger to have no correlation_id, got %v", got[3]["correlation_id"])
+	}
+}
+
+func TestLogger_WithCorrelation_SingleKey(t *testing.T) {
+	ch := make(chan []byte, 1)
+	logger, err := log.NewChannelLogger(log.Config{Service: "test-service", Env: "dev", Level: log.InfoLevel}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.WithCorrelation("journey-1").WithCorrelation("journey-2").Info("req-1", "replaced", nil)
+	if line := <-ch; bytes.Count(line, []byte(`"correlation_id"`)) != 1 {
+		t.Errorf("expected a single correlation_id key, got %s", line)
+	}
+}
+
+func TestLogger_WithCorrelationEmpty(t *testing.T) {
+	logger, _ := newTestLogger(t, log.Config{})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for empty correlation id, got none")
+		}
+	}()
+	logger.WithCorrelation(" ")
+}
+
+func TestLogger_WithDefaultTraceID(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	job := logger.WithDefaultTraceID("nightly-cleanup")
+	job.Info("", "removed expired sessions", nil)
+	job.With(log.String("table", "sessions")).Warn("", "slow delete", nil)
+	job.Info("req-1", "explicit wins", nil)
+	job.Retry("").Info("attempt", nil)
+	job.SlowQuery("", "DELETE FROM sessions", 2, 1)
+
+	got := entries()
+	for i, want := range []string{"nightly-cleanup", "nightly-cleanup", "req-1", "nightly-cleanup", "nightly-cleanup"} {
+		if got[i]["trace_id"] != want {
+			t.Errorf("entry %d: expected trace_id %q, got %v", i, want, got[i]["trace_id"])
+		}
+	}
+
+	for name, call := range map[string]func(){
+		"no default":    func() { logger.Info("", "panics", nil) },
+		"empty default": func() { logger.WithDefaultTraceID(" ") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			call()
+		})
+	}
+}
+
+func TestConfig_AllowEmptyTraceID(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{AllowEmptyTraceID: true})
+
+	logger.Info("", "batch finished", nil)
+	logger.With(log.String("job", "cleanup")).Warn("", "slow", nil)
+	logger.Info("req-1", "explicit", nil)
+	logger.WithDefaultTraceID("nightly").Info("", "default", nil)
+
+	got := entries()
+	for i, want := range []any{nil, nil, "req-1", "nightly"} {
+		if got[i]["trace_id"] != want {
+			t.Errorf("entry %d: expected trace_id %v, got %v", i, want, got[i]["trace_id"])
+		}
+	}
+	if _, ok := got[0]["trace_id"]; ok {
+		t.Errorf("expected no trace_id key, got %v", got[0])
+	}
+}
@@ -0,0 +1,219 @@
+package log
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxDiffDepth bounds how deep Diff descends into nested maps and structs.
+// Values below this depth are compared as a whole.
+const maxDiffDepth = 5
+
+// Diff creates a field describing what changed between old and new.
+// Maps and structs are compared key by key (struct keys honor json tags) and
+// the result is emitted as a nested object with up to three sections:
+//
+//	{
+//	  "added":   {"path": newValue},
+//	  "removed": {"path": oldValue},
+//	  "changed": {"path": {"old": oldValue, "new": newValue}}
+//	}
+//
+// Nested keys are joined with dots ("limits.max_conns"). Empty sections are
+// omitted, so identical values produce an empty object. Values that are not
+// maps or structs are compared as a whole and reported under the "$" path.
+//
+// Example:
+//
+//	logger.Info(traceId, "config updated", nil, log.Diff("config", oldCfg, newCfg))
+func Diff(key string, old, new any) Field {
+	d := &valueDiff{
+		added:   map[string]any{},
+		removed: map[string]any{},
+		changed: map[string][2]any{},
+	}
+	d.compare("", normalizeDiffValue(old, 0), normalizeDiffValue(new, 0), 0)
+	return Field{zapField: zap.Object(key, d)}
+}
+
+// valueDiff is the result of comparing two values.
+type valueDiff struct {
+	added   map[string]any
+	removed map[string]any
+	changed map[string][2]any
+}
+
+func (d *valueDiff) compare(path string, old, new any, depth int) {
+	oldMap, oldIsMap := old.(map[string]any)
+	newMap, newIsMap := new.(map[string]any)
+	if !oldIsMap || !newIsMap || depth >= maxDiffDepth {
+		if !diffEqual(old, new) {
+			if path == "" {
+				path = "$"
+			}
+			d.changed[path] = [2]any{old, new}
+		}
+		return
+	}
+
+	for k, oldValue := range oldMap {
+		newValue, exists := newMap[k]
+		if !exists {
+			d.removed[joinDiffPath(path, k)] = oldValue
+			continue
+		}
+		d.compare(joinDiffPath(path, k), oldValue, newValue, depth+1)
+	}
+	for k, newValue := range newMap {
+		if _, exists := oldMap[k]; !exists {
+			d.added[joinDiffPath(path, k)] = newValue
+		}
+	}
+}
+
+func (d *valueDiff) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if len(d.added) > 0 {
+		if err := enc.AddObject("added", diffValues(d.added)); err != nil {
+			return err
+		}
+	}
+	if len(d.removed) > 0 {
+		if err := enc.AddObject("removed", diffValues(d.removed)); err != nil {
+			return err
+		}
+	}
+	if len(d.changed) > 0 {
+		return enc.AddObject("changed", diffChanges(d.changed))
+	}
+	return nil
+}
+
+// diffValues encodes path -> value with paths in sorted order.
+type diffValues map[string]any
+
+func (v diffValues) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, path := range slices.Sorted(maps.Keys(v)) {
+		if err := enc.AddReflected(path, v[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffChanges encodes path -> {"old", "new"} with paths in sorted order.
+type diffChanges map[string][2]any
+
+func (c diffChanges) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, path := range slices.Sorted(maps.Keys(c)) {
+		change := c[path]
+		err := enc.AddObject(path, zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			if err := enc.AddReflected("old", change[0]); err != nil {
+				return err
+			}
+			return enc.AddReflected("new", change[1])
+		}))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func joinDiffPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// diffEqual reports whether two leaf values are equal. Times are equal if
+// they are the same instant, whatever their location.
+func diffEqual(old, new any) bool {
+	if oldTime, ok := old.(time.Time); ok {
+		newTime, ok := new.(time.Time)
+		return ok && oldTime.Equal(newTime)
+	}
+	return reflect.DeepEqual(old, new)
+}
+
+// isDiffLeaf reports whether value encodes itself, like time.Time, and is
+// therefore compared as a whole rather than field by field.
+func isDiffLeaf(value any) bool {
+	switch value.(type) {
+	case json.Marshaler, encoding.TextMarshaler, fmt.Stringer:
+		return true
+	}
+	return false
+}
+
+// normalizeDiffValue converts maps and structs into map[string]any so they can
+// be compared key by key. Values that encode themselves, such as time.Time,
+// and structs without exported fields are compared as a whole; they and other
+// values are returned unchanged, with pointers dereferenced.
+func normalizeDiffValue(value any, depth int) any {
+	if value == nil || depth > maxDiffDepth {
+		return value
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if isDiffLeaf(v.Interface()) {
+			break
+		}
+		v = v.Elem()
+	}
+	if isDiffLeaf(v.Interface()) {
+		if v.Kind() == reflect.Pointer {
+			v = v.Elem() // Compare what the pointers point to
+		}
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = normalizeDiffValue(iter.Value().Interface(), depth+1)
+		}
+		return out
+	case reflect.Struct:
+		t := v.Type()
+		out := make(map[string]any, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			name := sf.Name
+			if tag, ok := sf.Tag.Lookup("json"); ok {
+				tagName, _, _ := strings.Cut(tag, ",")
+				if tagName == "-" {
+					continue
+				}
+				if tagName != "" {
+					name = tagName
+				}
+			}
+			out[name] = normalizeDiffValue(v.Field(i).Interface(), depth+1)
+		}
+		if len(out) == 0 && t.NumField() > 0 {
+			return v.Interface() // Only unexported fields, which DeepEqual still sees
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,122 @@
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func TestDiff_Maps(t *testing.T) {
+	old := map[string]any{
+		"name":    "api",
+		"replica": 2,
+		"limits":  map[string]any{"max_conns": 100, "timeout": "5s"},
+	}
+	new := map[string]any{
+		"name":    "api",
+		"replica": 3,
+		"limits":  map[string]any{"max_conns": 200, "timeout": "5s"},
+		"region":  "eu-west-1",
+	}
+
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Info("req-123", "config updated", nil, log.Diff("config", old, new))
+
+	logEntry := entries()[0]
+	diff, ok := logEntry["config"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected config diff object, got %v", logEntry["config"])
+	}
+
+	added, _ := diff["added"].(map[string]any)
+	if added["region"] != "eu-west-1" {
+		t.Errorf("expected added region=eu-west-1, got %v", diff["added"])
+	}
+	if _, exists := diff["removed"]; exists {
+		t.Errorf("expected no removed section, got %v", diff["removed"])
+	}
+
+	changed, _ := diff["changed"].(map[string]any)
+	replica, _ := changed["replica"].(map[string]any)
+	if replica["old"] != float64(2) || replica["new"] != float64(3) {
+		t.Errorf("expected replica 2 -> 3, got %v", changed["replica"])
+	}
+	maxConns, _ := changed["limits.max_conns"].(map[string]any)
+	if maxConns["old"] != float64(100) || maxConns["new"] != float64(200) {
+		t.Errorf("expected limits.max_conns 100 -> 200, got %v", changed["limits.max_conns"])
+	}
+	if _, exists := changed["limits.timeout"]; exists {
+		t.Error("unchanged keys should not be reported")
+	}
+}
+
+func TestDiff_Structs(t *testing.T) {
+	type account struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		Secret string `json:"-"`
+	}
+
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Info("req-123", "account updated", nil, log.Diff("account",
+		account{ID: "acc-1", Status: "active", Secret: "a"},
+		&account{ID: "acc-1", Status: "suspended", Secret: "b"},
+	))
+
+	diff, _ := entries()[0]["account"].(map[string]any)
+	changed, _ := diff["changed"].(map[string]any)
+	if len(changed) != 1 {
+		t.Fatalf("expected exactly one change, got %v", changed)
+	}
+	status, _ := changed["status"].(map[string]any)
+	if status["old"] != "active" || status["new"] != "suspended" {
+		t.Errorf("expected status active -> suspended, got %v", changed["status"])
+	}
+}
+
+func TestDiff_Scalars(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Info("req-123", "state", nil, log.Diff("state", "pending", "done"), log.Diff("same", 1, 1))
+
+	logEntry := entries()[0]
+	diff, _ := logEntry["state"].(map[string]any)
+	changed, _ := diff["changed"].(map[string]any)
+	root, _ := changed["$"].(map[string]any)
+	if root["old"] != "pending" || root["new"] != "done" {
+		t.Errorf("expected $ pending -> done, got %v", diff)
+	}
+	if same, _ := logEntry["same"].(map[string]any); len(same) != 0 {
+		t.Errorf("expected empty diff for equal values, got %v", same)
+	}
+}
+
+func TestDiff_Times(t *testing.T) {
+	type lease struct {
+		Holder    string    `json:"holder"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	at := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Info("req-123", "lease renewed", nil,
+		log.Diff("lease", lease{Holder: "a", ExpiresAt: at}, lease{Holder: "a", ExpiresAt: at.Add(time.Minute)}),
+		log.Diff("t", time.Unix(0, 0), time.Unix(5, 0)),
+		log.Diff("same", at, at.In(time.FixedZone("CET", 3600))),
+	)
+
+	logEntry := entries()[0]
+	diff, _ := logEntry["lease"].(map[string]any)
+	changed, _ := diff["changed"].(map[string]any)
+	expiresAt, _ := changed["expires_at"].(map[string]any)
+	if len(changed) != 1 || expiresAt["old"] != "2025-01-15T10:30:00Z" || expiresAt["new"] != "2025-01-15T10:31:00Z" {
+		t.Errorf("expected expires_at to change, got %v", diff)
+	}
+	diff, _ = logEntry["t"].(map[string]any)
+	if changed, _ := diff["changed"].(map[string]any); changed["$"] == nil {
+		t.Errorf("expected different times to differ, got %v", logEntry["t"])
+	}
+	if same, _ := logEntry["same"].(map[string]any); len(same) != 0 {
+		t.Errorf("expected the same instant in another zone to be equal, got %v", same)
+	}
+}
@@ -0,0 +1,95 @@
+package log
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.uber.org/zap"
+)
+
+// DomainError returns the "error" field for err together with structured fields
+// for the domain-specific information it carries. Anywhere in err's chain:
+//   - an error with a Code() string method adds 'error_code'
+//   - an error with a Details() map[string]any method adds 'error_details'
+//
+// The first matching error in the chain is used for each. A nil err returns nil.
+//
+// Example:
+//
+//	logger.Error("req-123", "payment rejected", nil, log.DomainError(err)...)
+//	// "error": "...", "error_code": "card_declined", "error_details": {"reason": "insufficient_funds"}
+func DomainError(err error) []Field {
+	if err == nil {
+		return nil
+	}
+
+	fields := []Field{Error(err)}
+
+	var coder interface{ Code() string }
+	if errors.As(err, &coder) {
+		fields = append(fields, String("error_code", coder.Code()))
+	}
+
+	var detailer interface{ Details() map[string]any }
+	if errors.As(err, &detailer) {
+		if details := detailer.Details(); len(details) > 0 {
+			fields = append(fields, Any("error_details", details))
+		}
+	}
+
+	return fields
+}
+
+// fingerprintPatterns replace the variable parts of error messages for
+// ErrorFingerprint, in order.
+var fingerprintPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`"[^"]*"|'[^']*'|` + "`[^`]*`"), "<str>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b|\b[0-9a-f]*[0-9][0-9a-f]*[a-f][0-9a-f]*\b|\b[0-9a-f]*[a-f][0-9a-f]*[0-9][0-9a-f]*\b`), "<hex>"},
+	{regexp.MustCompile(`\d+(\.\d+)?`), "<n>"},
+}
+
+// ErrorFingerprint returns an 'error_fingerprint' field identifying err's
+// kind, so an error tracker can group errors whose messages differ only in
+// embedded values. The fingerprint is the first 16 hex digits of the SHA-256
+// of the error's type and its normalized message. The type is that of the
+// innermost error reached with errors.Unwrap. The message is normalized by
+// replacing, in order:
+//   - quoted text ("...", '...', or `...`) with <str>
+//   - UUIDs with <uuid>
+//   - 0x-prefixed hex numbers and words mixing hex letters and digits, such
+//     as hashes and hex IDs, with <hex>
+//   - remaining numbers, including decimals, with <n>
+//
+// So "order 1234 not found" and "order 98 not found" share a fingerprint,
+// and "user \"alice\" locked" and "user \"bob\" locked" do too. A nil err
+// returns a field that writes nothing.
+//
+// Example:
+//
+//	logger.Error("req-123", "load failed", nil, log.Error(err), log.ErrorFingerprint(err))
+//	// "error": "order 1234 not found", "error_fingerprint": "3f9c2b7d51a08e64"
+func ErrorFingerprint(err error) Field {
+	if err == nil {
+		return Field{zapField: zap.Skip()}
+	}
+
+	innermost := err
+	for next := errors.Unwrap(innermost); next != nil; next = errors.Unwrap(innermost) {
+		innermost = next
+	}
+
+	message := err.Error()
+	for _, p := range fingerprintPatterns {
+		message = p.pattern.ReplaceAllString(message, p.replacement)
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%T\x00%s", innermost, message)))
+	return String("error_fingerprint", hex.EncodeToString(sum[:8]))
+}
@@ -0,0 +1,94 @@
+package log_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+type paymentError struct {
+	code   string
+	reason string
+}
+
+func (e *paymentError) Error() string { return "payment failed: " + e.reason }
+func (e *paymentError) Code() string  { return e.code }
+func (e *paymentError) Details() map[string]any {
+	return map[string]any{"reason": e.reason}
+}
+
+func TestDomainError(t *testing.T) {
+	err := fmt.Errorf("charge order: %w", &paymentError{code: "card_declined", reason: "insufficient_funds"})
+
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Error("req-123", "payment rejected", nil, log.DomainError(err)...)
+
+	logEntry := entries()[0]
+	if logEntry["error"] != "charge order: payment failed: insufficient_funds" {
+		t.Errorf("expected wrapped error message, got %v", logEntry["error"])
+	}
+	if logEntry["error_code"] != "card_declined" {
+		t.Errorf("expected error_code=card_declined, got %v", logEntry["error_code"])
+	}
+	details, ok := logEntry["error_details"].(map[string]any)
+	if !ok || details["reason"] != "insufficient_funds" {
+		t.Errorf("expected error_details.reason=insufficient_funds, got %v", logEntry["error_details"])
+	}
+}
+
+func TestDomainError_PlainError(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Error("req-123", "failed", nil, log.DomainError(errors.New("boom"))...)
+
+	logEntry := entries()[0]
+	if logEntry["error"] != "boom" {
+		t.Errorf("expected error=boom, got %v", logEntry["error"])
+	}
+	for _, key := range []string{"error_code", "error_details"} {
+		if _, exists := logEntry[key]; exists {
+			t.Errorf("expected no %s for plain error, got %v", key, logEntry[key])
+		}
+	}
+
+	if fields := log.DomainError(nil); fields != nil {
+		t.Errorf("expected nil fields for nil error, got %v", fields)
+	}
+}
+
+func TestErrorFingerprint(t *testing.T) {
+	fingerprint := func(err error) string {
+		t.Helper()
+		logger, entries := newTestLogger(t, log.Config{})
+		logger.Error("req-123", "failed", nil, log.ErrorFingerprint(err))
+		value, _ := entries()[0]["error_fingerprint"].(string)
+		return value
+	}
+
+	same := [][2]error{
+		{errors.New("order 1234 not found"), errors.New("order 98 not found")},
+		{errors.New(`user "alice" locked`), errors.New(`user "bob" locked`)},
+		{errors.New("session 0b5e7a1c-2f4d-4e8a-9c3b-7d1e6f0a2b4c expired"), errors.New("session 9f2c3d4e-5a6b-4c7d-8e9f-0a1b2c3d4e5f expired")},
+		{errors.New("blob 3fa9c0d1 missing after 2.5s"), errors.New("blob e2b7 missing after 10s")},
+		{fmt.Errorf("load: %w", errors.New("timeout 30")), fmt.Errorf("load: %w", errors.New("timeout 45"))},
+	}
+	for _, pair := range same {
+		if a, b := fingerprint(pair[0]), fingerprint(pair[1]); a == "" || a != b {
+			t.Errorf("expected %q and %q to share a fingerprint, got %q and %q", pair[0], pair[1], a, b)
+		}
+	}
+
+	if fingerprint(errors.New("order 1 not found")) == fingerprint(errors.New("order 1 cancelled")) {
+		t.Error("expected different messages to have different fingerprints")
+	}
+	if fingerprint(errors.New("payment failed: declined")) == fingerprint(&paymentError{reason: "declined"}) {
+		t.Error("expected different innermost types to have different fingerprints")
+	}
+
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Error("req-123", "no error", nil, log.ErrorFingerprint(nil))
+	if _, ok := entries()[0]["error_fingerprint"]; ok {
+		t.Error("expected no field for a nil error")
+	}
+}
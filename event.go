@@ -0,0 +1,38 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Event types recognized across services, for Logger.Event.
+const (
+	EventRequest = "request" // Handling of an inbound request
+	EventJob     = "job"     // Background or scheduled work
+	EventAudit   = "audit"   // Security- or compliance-relevant action
+	EventMetric  = "metric"  // Entry carrying a measurement
+)
+
+// Event returns a child logger whose entries include an 'event_type' field,
+// for grouping entries by kind. Calling Event again replaces the type instead
+// of adding a second 'event_type' key. Use the Event constants where they fit.
+//
+// Example:
+//
+//	auditLogger := logger.Event(log.EventAudit)
+//	auditLogger.Info(traceID, "role granted", nil, log.String("role", "admin"))
+//
+// Panics if eventType is empty or only whitespace, or if Config.EventTypes is
+// set and does not list it.
+func (l *Logger) Event(eventType string) *Logger {
+	if strings.TrimSpace(eventType) == "" {
+		panic("log: event type cannot be empty")
+	}
+	if l.eventTypes != nil && !l.eventTypes[eventType] {
+		panic(fmt.Sprintf("log: event type %q is not in Config.EventTypes", eventType))
+	}
+
+	child := *l
+	child.eventType = eventType
+	return &child
+}
@@ -0,0 +1,54 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_Event(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	audit := logger.Event(log.EventAudit)
+	audit.Info("req-1", "role granted", nil)
+	audit.Event(log.EventJob).With(log.String("job", "cleanup")).Info("req-2", "replaced", nil)
+	logger.Info("req-3", "untyped", nil)
+
+	got := entries()
+	if got[0]["event_type"] != "audit" {
+		t.Errorf("expected event_type=audit, got %v", got[0]["event_type"])
+	}
+	if got[1]["event_type"] != "job" || got[1]["job"] != "cleanup" {
+		t.Errorf("expected event_type replaced with job, got %v", got[1])
+	}
+	if _, exists := got[2]["event_type"]; exists {
+		t.Errorf("expected parent logger to have no event_type, got %v", got[2]["event_type"])
+	}
+}
+
+func TestLogger_EventAllowlist(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{EventTypes: []string{log.EventRequest, "billing"}})
+
+	logger.Event("billing").Info("req-1", "allowed", nil)
+	if got := entries(); len(got) != 1 || got[0]["event_type"] != "billing" {
+		t.Errorf("expected an allowed custom event type, got %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for an event type outside the allowlist, got none")
+		}
+	}()
+	logger.Event("reqeust")
+}
+
+func TestLogger_EventEmpty(t *testing.T) {
+	logger, _ := newTestLogger(t, log.Config{})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for empty event type, got none")
+		}
+	}()
+	logger.Event("")
+}
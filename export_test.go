@@ -0,0 +1,8 @@
+package log
+
+// SetExit replaces the function Fatal exits with until restore is called.
+func SetExit(f func(code int)) (restore func()) {
+	previous := exit
+	exit = f
+	return func() { exit = previous }
+}
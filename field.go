@@ -1,6 +1,23 @@
 package log
 
-import "go.uber.org/zap"
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxSafeInteger is the largest integer that a float64 (and therefore a
+// JavaScript number) represents exactly: 2^53.
+const maxSafeInteger = 1 << 53
 
 // Field represents a structured log field (key-value pair).
 // It is an opaque type that wraps the underlying logging implementation.
@@ -9,6 +26,63 @@ type Field struct {
 	zapField zap.Field
 }
 
+// ErrInvalidFieldKey is wrapped by the errors Field.Valid returns.
+var ErrInvalidFieldKey = errors.New("log: invalid field key")
+
+// reservedFieldKeys are the keys every entry already has, under their
+// default names.
+var reservedFieldKeys = []string{"timestamp", "level", "message", "service", "env", "trace_id", "metadata"}
+
+// Key returns the field's key. Fields that write several keys, such as
+// Attempt, and fields that write nothing, such as MustLog, have no key of
+// their own and return "".
+func (f Field) Key() string {
+	return f.zapField.Key
+}
+
+// Valid reports whether the field's keys can be written without producing
+// a broken or ambiguous entry. It returns an error wrapping
+// ErrInvalidFieldKey if a key is empty or is one every entry already has:
+// timestamp, level, message, service, env, trace_id, or metadata. Use it
+// to check fields built from user input before logging them.
+//
+// Example:
+//
+//	for key, value := range userAttributes {
+//	    field := log.String(key, value)
+//	    if err := field.Valid(); err != nil {
+//	        continue
+//	    }
+//	    fields = append(fields, field)
+//	}
+func (f Field) Valid() error {
+	switch f.zapField.Type {
+	case zapcore.SkipType:
+		return nil
+	case zapcore.InlineMarshalerType:
+		enc := zapcore.NewMapObjectEncoder()
+		f.zapField.AddTo(enc)
+		for _, key := range slices.Sorted(maps.Keys(enc.Fields)) {
+			if err := validFieldKey(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return validFieldKey(f.zapField.Key)
+	}
+}
+
+func validFieldKey(key string) error {
+	if strings.TrimSpace(key) == "" {
+		return fmt.Errorf("%w: key is empty", ErrInvalidFieldKey)
+	}
+	if slices.Contains(reservedFieldKeys, key) {
+		return fmt.Errorf("%w: %q is reserved", ErrInvalidFieldKey, key)
+	}
+	return nil
+}
+
 // String creates a field with a string value.
 func String(key, value string) Field {
 	return Field{zapField: zap.String(key, value)}
@@ -24,6 +98,11 @@ func Int64(key string, value int64) Field {
 	return Field{zapField: zap.Int64(key, value)}
 }
 
+// Uint64 creates a field with a uint64 value.
+func Uint64(key string, value uint64) Field {
+	return Field{zapField: zap.Uint64(key, value)}
+}
+
 // Float64 creates a field with a float64 value.
 func Float64(key string, value float64) Field {
 	return Field{zapField: zap.Float64(key, value)}
@@ -34,6 +113,17 @@ func Bool(key string, value bool) Field {
 	return Field{zapField: zap.Bool(key, value)}
 }
 
+// Enum creates a field from a string-backed type, such as a status or kind
+// constant, without a string(...) conversion at every call site.
+//
+// Example:
+//
+//	type Status string
+//	logger.Info("req-123", "order updated", nil, log.Enum("status", order.Status))
+func Enum[T ~string](key string, value T) Field {
+	return String(key, string(value))
+}
+
 // Any creates a field with any type of value.
 // The value will be JSON-marshaled in the log output.
 // Use this for complex types like maps, structs, and slices.
@@ -41,6 +131,151 @@ func Any(key string, value any) Field {
 	return Field{zapField: zap.Any(key, value)}
 }
 
+// SortedMap creates a field with a map as a nested object whose keys are
+// written in lexical order, so the output is the same on every run. Nested
+// map[string]any values are sorted too. Use it for maps in entries that are
+// diffed or compared against golden files.
+//
+// Example:
+//
+//	logger.Info("req-123", "flags evaluated", nil, log.SortedMap("flags", flags))
+func SortedMap(key string, m map[string]any) Field {
+	return Field{zapField: zap.Object(key, sortedMap(m))}
+}
+
+// sortedMap encodes a map as an object with its keys in lexical order.
+type sortedMap map[string]any
+
+func (m sortedMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		var err error
+		if nested, ok := m[key].(map[string]any); ok {
+			err = enc.AddObject(key, sortedMap(nested))
+		} else {
+			err = enc.AddReflected(key, m[key])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IP creates a field with an IP address formatted as a string,
+// e.g. "192.168.1.1" or "2001:db8::1". A nil IP is logged as an empty string.
+func IP(key string, ip net.IP) Field {
+	if ip == nil {
+		return String(key, "")
+	}
+	return String(key, ip.String())
+}
+
+// Addr creates a field with a network address formatted as a string,
+// e.g. "192.168.1.1:8080". A nil address is logged as an empty string.
+func Addr(key string, addr net.Addr) Field {
+	if addr == nil {
+		return String(key, "")
+	}
+	return String(key, addr.String())
+}
+
+// URL creates a field with a URL formatted as a string. Any userinfo (such as
+// basic-auth credentials or tokens) is replaced with "xxxxx" so it never
+// reaches the logs. A nil URL is logged as an empty string.
+func URL(key string, u *url.URL) Field {
+	if u == nil {
+		return String(key, "")
+	}
+	if u.User != nil {
+		redacted := *u
+		redacted.User = url.User("xxxxx")
+		return String(key, redacted.String())
+	}
+	return String(key, u.String())
+}
+
+// Query creates a field with URL query parameters as a nested object, keys in
+// lexical order. A parameter with one value is logged as a string, and one
+// with several values as an array. The values of parameters named in redact
+// (compared case-insensitively) are replaced with "[REDACTED]".
+//
+// Example:
+//
+//	logger.Info("req-123", "request received", nil, log.Query("query", r.URL.Query(), "token", "api_key"))
+//	// "query": {"api_key": "[REDACTED]", "page": "2", "tag": ["a", "b"]}
+func Query(key string, values url.Values, redact ...string) Field {
+	return Field{zapField: zap.Object(key, queryValues{values: values, redact: redact})}
+}
+
+// queryValues encodes url.Values as an object, see Query.
+type queryValues struct {
+	values url.Values
+	redact []string
+}
+
+func (q queryValues) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, key := range slices.Sorted(maps.Keys(q.values)) {
+		values := q.values[key]
+		switch {
+		case slices.ContainsFunc(q.redact, func(name string) bool { return strings.EqualFold(name, key) }):
+			enc.AddString(key, redactedValue)
+		case len(values) == 1:
+			enc.AddString(key, values[0])
+		default:
+			if err := enc.AddArray(key, zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+				for _, value := range values {
+					enc.AppendString(value)
+				}
+				return nil
+			})); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Interval creates a field with a time range as a nested object:
+//
+//	"window": {"start": "...", "end": "...", "duration_ms": 60000}
+//
+// start and end are formatted like the entry timestamp. duration_ms is
+// end minus start in whole milliseconds and is negative if end is before start.
+func Interval(key string, start, end time.Time) Field {
+	return Field{zapField: zap.Object(key, zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddTime("start", start)
+		enc.AddTime("end", end)
+		enc.AddInt64("duration_ms", end.Sub(start).Milliseconds())
+		return nil
+	}))}
+}
+
+// Time creates a field with a timestamp, encoded like the entry's own
+// timestamp: ISO 8601 with milliseconds and the zone offset, converted to UTC
+// when Config.TimeUTC is set.
+//
+// Example:
+//
+//	logger.Info(traceID, "session created", nil, log.Time("expires_at", session.ExpiresAt))
+//	// "expires_at": "2025-01-15T11:30:00.000Z"
+func Time(key string, value time.Time) Field {
+	return Field{zapField: zap.Time(key, value)}
+}
+
+// HumanDuration creates a string field with d in Go's compound format, such as
+// "1m30s" or "250ms", for entries read by people, like the console mirror.
+// Dashboards that compute with durations are better served by a numeric
+// field, such as Int64(key+"_ms", d.Milliseconds()), or by Any, which writes
+// a time.Duration as seconds.
+//
+// Example:
+//
+//	logger.Info(traceID, "backup finished", nil, log.HumanDuration("took", elapsed))
+//	// "took": "1m30s"
+func HumanDuration(key string, d time.Duration) Field {
+	return Field{zapField: zap.String(key, d.String())}
+}
+
 // Error creates an error field with the key "error".
 // The error message and type will be included in the log output.
 func Error(err error) Field {
@@ -54,3 +289,39 @@ func toZapFields(fields []Field) []zap.Field {
 	}
 	return zapFields
 }
+
+// isEmptyField reports whether f is a string field with an empty or
+// whitespace-only value or an Any field with a nil value.
+func isEmptyField(f zap.Field) bool {
+	switch f.Type {
+	case zapcore.StringType:
+		return strings.TrimSpace(f.String) == ""
+	case zapcore.ReflectType:
+		return f.Interface == nil
+	}
+	return false
+}
+
+// stringifyLargeNumber re-encodes integer fields beyond ±2^53 as strings so
+// JSON parsers that decode numbers as doubles don't lose precision.
+func stringifyLargeNumber(f zap.Field) zap.Field {
+	switch f.Type {
+	case zapcore.Int64Type:
+		if f.Integer > maxSafeInteger || f.Integer < -maxSafeInteger {
+			return zap.String(f.Key, strconv.FormatInt(f.Integer, 10))
+		}
+	case zapcore.Uint64Type:
+		if uint64(f.Integer) > maxSafeInteger {
+			return zap.String(f.Key, strconv.FormatUint(uint64(f.Integer), 10))
+		}
+	}
+	return f
+}
+
+// stringifyBool converts a Bool field to a String field of "true" or "false".
+func stringifyBool(f zap.Field) zap.Field {
+	if f.Type == zapcore.BoolType {
+		return zap.String(f.Key, strconv.FormatBool(f.Integer == 1))
+	}
+	return f
+}
@@ -0,0 +1,33 @@
+package log
+
+import "cmp"
+
+// FieldNames renames the fields that identify the service in every entry, for
+// ingestion pipelines with fixed key names. Empty names keep the defaults.
+//
+// Example (OpenTelemetry resource attribute names):
+//
+//	FieldNames: log.FieldNames{
+//	    ServiceKey: "service.name",
+//	    EnvKey:     "deployment.environment",
+//	    VersionKey: "service.version",
+//	},
+type FieldNames struct {
+	// ServiceKey is the key of Config.Service. Default: "service".
+	ServiceKey string
+
+	// EnvKey is the key of Config.Env. Default: "env" ("dd.env" with SchemaDatadog).
+	EnvKey string
+
+	// VersionKey is the key of Config.Version. Default: "version".
+	VersionKey string
+}
+
+// resolve returns the service, env, and version keys in effect for schema.
+func (n FieldNames) resolve(schema Schema) (service, env, version string) {
+	defaultEnv := "env"
+	if schema == SchemaDatadog {
+		defaultEnv = "dd.env"
+	}
+	return cmp.Or(n.ServiceKey, "service"), cmp.Or(n.EnvKey, defaultEnv), cmp.Or(n.VersionKey, "version")
+}
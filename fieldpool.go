@@ -0,0 +1,47 @@
+package log
+
+import "sync"
+
+// fieldSlices pools FieldSlices for NewFieldSlice.
+var fieldSlices = sync.Pool{
+	New: func() any { return &FieldSlice{} },
+}
+
+// FieldSlice is a reusable slice of fields for hot paths that would otherwise
+// allocate a new []Field on every log call. Get one with NewFieldSlice, and
+// call Release once the log call has returned. A FieldSlice is not safe for
+// concurrent use; each goroutine should get its own.
+type FieldSlice struct {
+	Fields []Field
+}
+
+// NewFieldSlice returns an empty FieldSlice from a pool, with room for at
+// least capacity fields.
+//
+// Example:
+//
+//	fs := log.NewFieldSlice(3)
+//	fs.Append(log.String("user_id", userID), log.Int("status", status), log.Bool("cached", cached))
+//	logger.Info(traceID, "request handled", nil, fs.Fields...)
+//	fs.Release()
+func NewFieldSlice(capacity int) *FieldSlice {
+	fs := fieldSlices.Get().(*FieldSlice)
+	if cap(fs.Fields) < capacity {
+		fs.Fields = make([]Field, 0, capacity)
+	}
+	return fs
+}
+
+// Append adds fields to the slice.
+func (fs *FieldSlice) Append(fields ...Field) {
+	fs.Fields = append(fs.Fields, fields...)
+}
+
+// Release clears the slice and returns it to the pool. The logger does not
+// keep fields after a log call returns, so Release is safe right after
+// logging, but fs and fs.Fields must not be used afterwards.
+func (fs *FieldSlice) Release() {
+	clear(fs.Fields) // Drop references to logged values
+	fs.Fields = fs.Fields[:0]
+	fieldSlices.Put(fs)
+}
@@ -0,0 +1,61 @@
+package log_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestNewFieldSlice(t *testing.T) {
+	fs := log.NewFieldSlice(4)
+	if len(fs.Fields) != 0 || cap(fs.Fields) < 4 {
+		t.Fatalf("expected an empty slice with capacity >= 4, got len=%d cap=%d", len(fs.Fields), cap(fs.Fields))
+	}
+	fs.Append(log.String("a", "1"), log.Int("b", 2))
+	if len(fs.Fields) != 2 {
+		t.Errorf("expected 2 fields, got %d", len(fs.Fields))
+	}
+	fs.Release()
+
+	if reused := log.NewFieldSlice(1); len(reused.Fields) != 0 {
+		t.Errorf("expected a released slice to come back empty, got %d fields", len(reused.Fields))
+	}
+}
+
+func TestNewFieldSlice_Concurrent(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	const goroutines, perGoroutine = 8, 50
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perGoroutine {
+				fs := log.NewFieldSlice(2)
+				id := fmt.Sprintf("%d-%d", g, i)
+				fs.Append(log.String("id", id), log.String("copy", id))
+				logger.Info("req-123", "pooled", nil, fs.Fields...)
+				fs.Release()
+			}
+		}()
+	}
+	wg.Wait()
+
+	got := entries()
+	if len(got) != goroutines*perGoroutine {
+		t.Fatalf("expected %d entries, got %d", goroutines*perGoroutine, len(got))
+	}
+	seen := make(map[any]bool)
+	for _, logEntry := range got {
+		if logEntry["id"] != logEntry["copy"] {
+			t.Errorf("expected fields from a single slice, got id=%v copy=%v", logEntry["id"], logEntry["copy"])
+		}
+		if seen[logEntry["id"]] {
+			t.Errorf("id %v logged twice", logEntry["id"])
+		}
+		seen[logEntry["id"]] = true
+	}
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// FlagEval creates a field recording one feature flag evaluation, keyed by
+// the flag name, with the value it evaluated to and, when not empty, the
+// reason the flag system gave:
+//
+//	"new_checkout": {"value": true, "reason": "targeting_match"}
+//
+// Example:
+//
+//	logger.Info("req-123", "checkout started", nil, log.FlagEval("new_checkout", enabled, "targeting_match"))
+func FlagEval(flag string, value any, reason string) Field {
+	return Field{zapField: zap.Object(flag, zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		if err := enc.AddReflected("value", value); err != nil {
+			return err
+		}
+		if reason != "" {
+			enc.AddString("reason", reason)
+		}
+		return nil
+	}))}
+}
+
+// LogFlags logs the feature flags evaluated for a request as one info entry
+// with the message "feature flags evaluated", 'log_type' "flag_eval", and
+// 'flags', the evaluations keyed by flag name in sorted order. Nothing is
+// logged when evals is empty.
+//
+// Example:
+//
+//	logger.LogFlags(traceId, map[string]any{"new_checkout": true, "search_ranking": "v2"})
+//	// "log_type": "flag_eval", "flags": {"new_checkout": true, "search_ranking": "v2"}
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+func (l *Logger) LogFlags(traceId string, evals map[string]any, fields ...Field) {
+	traceId = l.resolveTraceID(traceId)
+	if len(evals) == 0 {
+		return
+	}
+
+	l.log(zapcore.InfoLevel, 1, time.Time{}, traceId, "feature flags evaluated", nil, append([]Field{
+		String("log_type", "flag_eval"),
+		SortedMap("flags", evals),
+	}, fields...))
+}
@@ -0,0 +1,52 @@
+package log_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestFieldHelpers_FlagEval(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Info("req-123", "checkout started", nil,
+		log.FlagEval("new_checkout", true, "targeting_match"),
+		log.FlagEval("search_ranking", "v2", ""),
+	)
+
+	logEntry := entries()[0]
+	checkout, _ := logEntry["new_checkout"].(map[string]any)
+	if checkout["value"] != true || checkout["reason"] != "targeting_match" {
+		t.Errorf("expected value and reason, got %v", logEntry["new_checkout"])
+	}
+	ranking, _ := logEntry["search_ranking"].(map[string]any)
+	if _, exists := ranking["reason"]; ranking["value"] != "v2" || exists {
+		t.Errorf("expected value without reason, got %v", logEntry["search_ranking"])
+	}
+}
+
+func TestLogger_LogFlags(t *testing.T) {
+	ch := make(chan []byte, 16)
+	logger, err := log.NewChannelLogger(log.Config{Service: "test-service", Env: "dev", Level: log.InfoLevel}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.LogFlags("req-123", nil)
+	logger.LogFlags("req-123", map[string]any{"search_ranking": "v2", "new_checkout": true}, log.String("user_id", "user-456"))
+
+	if len(ch) != 1 {
+		t.Fatalf("expected one entry, got %d", len(ch))
+	}
+	line := string(<-ch)
+	for _, want := range []string{
+		`"message":"feature flags evaluated"`,
+		`"log_type":"flag_eval"`,
+		`"flags":{"new_checkout":true,"search_ranking":"v2"}`,
+		`"user_id":"user-456"`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("expected %s in %s", want, line)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package log
+
+import "github.com/glennprays/log/internal/zapimpl"
+
+// Format specifies how log entries are encoded.
+type Format string
+
+const (
+	// FormatJSON encodes each entry as a single-line JSON object.
+	// This is the default and recommended for log collectors.
+	FormatJSON Format = "json"
+
+	// FormatCEF encodes each entry as an ArcSight Common Event Format line
+	// for SIEM ingestion. See the README for the field-to-CEF mapping.
+	FormatCEF Format = "cef"
+
+	// FormatCSV encodes each entry as a CSV row, with columns in the order of
+	// Config.CSVColumns, for spreadsheet-based analysis. The header row is
+	// written when the logger is created, so it is never dropped with an entry;
+	// file outputs get it at the start of each new or empty file instead.
+	FormatCSV Format = "csv"
+
+	// FormatConsole encodes each entry as a human-readable line, for reading
+	// logs while developing locally. Levels are colored when the output is a
+	// terminal. Not meant for log collectors.
+	FormatConsole Format = "console"
+)
+
+// CSVOtherFields is a column name for Config.CSVColumns. The column holds, as
+// a JSON object, every field that has no column of its own; without it, such
+// fields are dropped.
+const CSVOtherFields = zapimpl.CSVOtherFields
+
+// DefaultCSVColumns are the CSV columns used when Config.CSVColumns is empty.
+var DefaultCSVColumns = []string{"timestamp", "level", "message", "trace_id", "metadata", CSVOtherFields}
+
+// Line endings for Config.LineEnding.
+const (
+	// LineEndingLF ends each entry with "\n". This is the default.
+	LineEndingLF = "\n"
+
+	// LineEndingCRLF ends each entry with "\r\n", for Windows consumers.
+	LineEndingCRLF = "\r\n"
+
+	// LineEndingNone writes entries without a line ending, for writers that
+	// frame entries themselves. It is a name rather than the empty string,
+	// which selects the default.
+	LineEndingNone = "none"
+)
+
+// String returns the string representation of the Format.
+func (f Format) String() string {
+	return string(f)
+}
+
+// lineEnding returns the bytes Config.LineEnding writes after each entry.
+func lineEnding(name string) string {
+	if name == LineEndingNone {
+		return ""
+	}
+	return name
+}
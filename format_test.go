@@ -0,0 +1,512 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func TestFormat_CEF(t *testing.T) {
+	ch := make(chan []byte, 4)
+	logger, err := log.NewChannelLogger(log.Config{
+		Service:    "payments",
+		Env:        "production",
+		Level:      log.InfoLevel,
+		Format:     log.FormatCEF,
+		CEFVendor:  "Acme",
+		CEFVersion: "1.4",
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.Error("req-123", "login failed | bad password", map[string]any{"ip": "10.0.0.1"},
+		log.String("user", "a=b\nc"),
+	)
+
+	line := string(<-ch)
+	wantHeader := `CEF:0|Acme|payments|1.4|login failed \| bad password|login failed \| bad password|8|rt=`
+	if !strings.HasPrefix(line, wantHeader) {
+		t.Errorf("expected header %q, got %q", wantHeader, line)
+	}
+	for _, want := range []string{
+		` env=production`,
+		` trace_id=req-123`,
+		` metadata={"ip":"10.0.0.1"}`,
+		` user=a\=b\nc`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("expected extension to contain %q, got %q", want, line)
+		}
+	}
+	if !strings.HasSuffix(line, "\n") || strings.Count(line, "\n") != 1 {
+		t.Errorf("expected exactly one line, got %q", line)
+	}
+}
+
+func TestFormat_CSV(t *testing.T) {
+	ch := make(chan []byte, 4)
+	logger, err := log.NewChannelLogger(log.Config{
+		Service:    "batch",
+		Env:        "production",
+		Level:      log.InfoLevel,
+		Format:     log.FormatCSV,
+		CSVColumns: []string{"level", "message", "user_id", "count", log.CSVOtherFields},
+		Clock:      fixedClock{now: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)},
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	child := logger.With(log.String("user_id", "user-1"))
+	child.Info("req-1", "imported, with \"quotes\"", nil, log.Int("count", 3))
+	logger.Warn("req-2", "multi\nline", nil)
+
+	want := []string{
+		"level,message,user_id,count,other_fields\n",
+		`info,"imported, with ""quotes""",user-1,3,"{""timestamp"":""2025-01-15T10:30:00.000Z"",""service"":""batch"",""env"":""production"",""trace_id"":""req-1"",""metadata"":null}"` + "\n",
+		`warn,"multi` + "\n" + `line",,,"{""timestamp"":""2025-01-15T10:30:00.000Z"",""service"":""batch"",""env"":""production"",""trace_id"":""req-2"",""metadata"":null}"` + "\n",
+	}
+	for i, wantRow := range want {
+		if got := string(<-ch); got != wantRow {
+			t.Errorf("entry %d: expected\n%q\ngot\n%q", i, wantRow, got)
+		}
+	}
+}
+
+func TestFormat_CSVDefaultColumns(t *testing.T) {
+	ch := make(chan []byte, 4)
+	logger, err := log.NewChannelLogger(log.Config{
+		Service: "batch",
+		Env:     "production",
+		Level:   log.InfoLevel,
+		Format:  log.FormatCSV,
+		Clock:   fixedClock{now: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)},
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.Info("req-1", "done", map[string]any{"rows": 10})
+
+	if header := string(<-ch); header != "timestamp,level,message,trace_id,metadata,other_fields\n" {
+		t.Errorf("unexpected header %q", header)
+	}
+	if row := string(<-ch); !strings.HasPrefix(row, `2025-01-15T10:30:00.000Z,info,done,req-1,"{""rows"":10}",`) {
+		t.Errorf("unexpected row %q", row)
+	}
+}
+
+func TestFormat_CSVHeaderWithoutEntries(t *testing.T) {
+	ch := make(chan []byte, 4)
+	logger, err := log.NewChannelLogger(log.Config{
+		Service:           "batch",
+		Env:               "production",
+		Level:             log.InfoLevel,
+		Format:            log.FormatCSV,
+		CSVColumns:        []string{"level", "message"},
+		MaxBytesPerSecond: 1,
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.Info("req-1", "dropped by the byte limit", nil)
+	if header := string(<-ch); header != "level,message\n" {
+		t.Errorf("expected the header to be written when the logger is created, got %q", header)
+	}
+}
+
+func TestFormat_CSVFileReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.csv")
+	cfg := log.Config{
+		Service:    "batch",
+		Env:        "production",
+		Level:      log.InfoLevel,
+		Output:     log.OutputFile,
+		FilePath:   path,
+		Format:     log.FormatCSV,
+		CSVColumns: []string{"level", "message"},
+	}
+
+	for _, message := range []string{"first run", "second run"} {
+		logger, err := log.New(cfg)
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		logger.Info("req-1", message, nil)
+		if err := logger.Close(); err != nil {
+			t.Fatalf("failed to close logger: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if want := "level,message\ninfo,first run\ninfo,second run\n"; string(content) != want {
+		t.Errorf("expected one header when appending, got %q", content)
+	}
+}
+
+func TestFormat_CSVFileRotation(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := log.New(log.Config{
+		Service:    "batch",
+		Env:        "production",
+		Level:      log.InfoLevel,
+		Output:     log.OutputFile,
+		FilePath:   filepath.Join(dir, "app.csv"),
+		MaxSizeMB:  1,
+		Format:     log.FormatCSV,
+		CSVColumns: []string{"level", "message"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	message := strings.Repeat("x", 100*1024)
+	for range 12 {
+		logger.Info("req-1", message, nil)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("failed to close logger: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "app*.csv"))
+	if err != nil || len(files) != 2 {
+		t.Fatalf("expected the file to rotate once, got %v (%v)", files, err)
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		if !bytes.HasPrefix(content, []byte("level,message\ninfo,x")) {
+			t.Errorf("expected %s to start with the header, got %q", file, content[:min(len(content), 32)])
+		}
+		if n := bytes.Count(content, []byte("level,message\n")); n != 1 {
+			t.Errorf("expected one header in %s, got %d", file, n)
+		}
+	}
+}
+
+func TestFormat_CSVInvalidColumns(t *testing.T) {
+	for name, cfg := range map[string]log.Config{
+		"not csv":   {Format: log.FormatJSON, CSVColumns: []string{"message"}},
+		"duplicate": {Format: log.FormatCSV, CSVColumns: []string{"message", "message"}},
+		"empty":     {Format: log.FormatCSV, CSVColumns: []string{"message", ""}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg.Service, cfg.Env, cfg.Level, cfg.Output = "batch", "dev", log.InfoLevel, log.OutputStdout
+			if err := cfg.Validate(); err == nil {
+				t.Error("expected validation error, got nil")
+			}
+		})
+	}
+}
+
+func TestFormat_CEFSeverity(t *testing.T) {
+	ch := make(chan []byte, 4)
+	logger, err := log.NewChannelLogger(log.Config{
+		Service: "payments",
+		Env:     "dev",
+		Level:   log.DebugLevel,
+		Format:  log.FormatCEF,
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.Debug("req-1", "msg", nil)
+	logger.Info("req-2", "msg", nil)
+	logger.Warn("req-3", "msg", nil)
+	logger.Error("req-4", "msg", nil)
+
+	for _, severity := range []string{"1", "3", "5", "8"} {
+		line := string(<-ch)
+		want := "CEF:0|payments|payments|0|msg|msg|" + severity + "|"
+		if !strings.HasPrefix(line, want) {
+			t.Errorf("expected prefix %q, got %q", want, line)
+		}
+	}
+}
+
+func TestFormat_Console(t *testing.T) {
+	ch := make(chan []byte, 4)
+	logger, err := log.NewChannelLogger(log.Config{
+		Service:      "payments",
+		Env:          "dev",
+		Level:        log.InfoLevel,
+		Format:       log.FormatConsole,
+		EnableCaller: true,
+		TimeUTC:      true,
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	at := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	logger.InfoAt(at, "req-123", "user logged in", nil, log.String("user_id", "u-1"))
+
+	line := string(<-ch)
+	if !strings.HasPrefix(line, "2025-01-15T10:30:00.000Z\tINFO\tuser logged in\t{") {
+		t.Errorf("expected human-readable line, got %q", line)
+	}
+	for _, want := range []string{`"trace_id": "req-123"`, `"user_id": "u-1"`, `"caller": "format_test.go:`, `"function": "`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("expected line to contain %s, got %q", want, line)
+		}
+	}
+	if !strings.HasSuffix(line, "\n") || strings.Count(line, "\n") != 1 {
+		t.Errorf("expected exactly one line, got %q", line)
+	}
+}
+
+func TestFormat_Invalid(t *testing.T) {
+	_, err := log.New(log.Config{
+		Service: "test-service",
+		Env:     "dev",
+		Level:   log.InfoLevel,
+		Output:  log.OutputStdout,
+		Format:  "xml",
+	})
+	if err == nil {
+		t.Error("expected error for unknown format, got nil")
+	}
+}
+
+func TestFormat_SortFields(t *testing.T) {
+	ch := make(chan []byte, 4)
+	logger, err := log.NewChannelLogger(log.Config{
+		Service:    "payments",
+		Env:        "dev",
+		Level:      log.InfoLevel,
+		SortFields: true,
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	at := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	logger.With(log.String("zone", "eu"), log.String("account", "a-1")).
+		InfoAt(at, "req-123", "charged", nil, log.Int("amount", 42), log.Bool("retry", false))
+
+	want := `{"timestamp":"2025-01-15T10:30:00.000Z","level":"info","message":"charged","service":"payments","env":"dev","trace_id":"req-123","metadata":null,"account":"a-1","amount":42,"retry":false,"zone":"eu"}` + "\n"
+	if got := string(<-ch); got != want {
+		t.Errorf("unexpected sorted entry:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestFormat_ConsoleMirror(t *testing.T) {
+	var console bytes.Buffer
+	logger, entries := newTestLogger(t, log.Config{
+		Console:       true,
+		ConsoleWriter: &console,
+	})
+
+	at := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	logger.InfoAt(at, "req-123", "user logged in", nil, log.String("user_id", "u-1"))
+
+	if got := entries(); len(got) != 1 || got[0]["message"] != "user logged in" {
+		t.Fatalf("expected JSON entry on the main output, got %v", got)
+	}
+
+	line := console.String()
+	if !strings.HasPrefix(line, "2025-01-15T10:30:00.000Z\tINFO\tuser logged in\t{") {
+		t.Errorf("expected human-readable console line, got %q", line)
+	}
+	if !strings.Contains(line, `"trace_id": "req-123"`) || !strings.Contains(line, `"user_id": "u-1"`) {
+		t.Errorf("expected console line to include fields, got %q", line)
+	}
+}
+
+func TestFormat_LeadingKeys(t *testing.T) {
+	at := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		cfg  log.Config
+		want string
+	}{
+		{
+			name: "default",
+			want: `{"level":"info","timestamp":"2025-01-15T10:30:00.000Z","message":"charged","service":"payments","env":"dev","zone":"eu","amount":42,"trace_id":"req-123","metadata":null}`,
+		},
+		{
+			name: "message first",
+			cfg:  log.Config{MessageFirst: true},
+			want: `{"message":"charged","level":"info","timestamp":"2025-01-15T10:30:00.000Z","service":"payments","env":"dev","zone":"eu","amount":42,"trace_id":"req-123","metadata":null}`,
+		},
+		{
+			name: "message first and sorted",
+			cfg:  log.Config{MessageFirst: true, SortFields: true},
+			want: `{"message":"charged","level":"info","timestamp":"2025-01-15T10:30:00.000Z","service":"payments","env":"dev","trace_id":"req-123","metadata":null,"amount":42,"zone":"eu"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := make(chan []byte, 1)
+			tt.cfg.Service = "payments"
+			tt.cfg.Env = "dev"
+			tt.cfg.Level = log.InfoLevel
+			logger, err := log.NewChannelLogger(tt.cfg, ch)
+			if err != nil {
+				t.Fatalf("failed to create logger: %v", err)
+			}
+
+			logger.With(log.String("zone", "eu")).InfoAt(at, "req-123", "charged", nil, log.Int("amount", 42))
+
+			if got := string(<-ch); got != tt.want+"\n" {
+				t.Errorf("unexpected entry:\n got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat_LinePrefixSuffix(t *testing.T) {
+	ch := make(chan []byte, 2)
+	logger, err := log.NewChannelLogger(log.Config{
+		Service:    "payments",
+		Env:        "dev",
+		Level:      log.InfoLevel,
+		LinePrefix: "[app] ",
+		LineSuffix: " [/app]",
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.Info("req-123", "charged", nil)
+	line := string(<-ch)
+	if !strings.HasPrefix(line, `[app] {"level":"info"`) || !strings.HasSuffix(line, "} [/app]\n") {
+		t.Errorf("expected a framed single line, got %q", line)
+	}
+	if strings.Count(line, "\n") != 1 {
+		t.Errorf("expected one line ending, got %q", line)
+	}
+}
+
+func TestFormat_LinePrefixWithLineBreak(t *testing.T) {
+	_, err := log.New(log.Config{
+		Service:    "payments",
+		Env:        "dev",
+		Level:      log.InfoLevel,
+		Output:     log.OutputStdout,
+		LinePrefix: "a\nb",
+	})
+	if err == nil || !strings.Contains(err.Error(), "line prefix") {
+		t.Errorf("expected a line prefix error, got %v", err)
+	}
+}
+
+func TestFormat_LineEnding(t *testing.T) {
+	tests := []struct {
+		name       string
+		lineEnding string
+		cfg        log.Config
+		wantSuffix string
+	}{
+		{"default", "", log.Config{}, "}\n"},
+		{"crlf", log.LineEndingCRLF, log.Config{}, "}\r\n"},
+		{"none", log.LineEndingNone, log.Config{}, "}"},
+		{"sorted crlf", log.LineEndingCRLF, log.Config{SortFields: true}, "}\r\n"},
+		{"sorted none", log.LineEndingNone, log.Config{SortFields: true}, "}"},
+		{"framed crlf", log.LineEndingCRLF, log.Config{LineSuffix: " [/app]"}, "} [/app]\r\n"},
+		{"cef none", log.LineEndingNone, log.Config{Format: log.FormatCEF}, "metadata=null"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := make(chan []byte, 1)
+			cfg := tt.cfg
+			cfg.Service, cfg.Env, cfg.Level, cfg.LineEnding = "payments", "dev", log.InfoLevel, tt.lineEnding
+			logger, err := log.NewChannelLogger(cfg, ch)
+			if err != nil {
+				t.Fatalf("failed to create logger: %v", err)
+			}
+
+			logger.Info("req-123", "charged", nil)
+			line := string(<-ch)
+			if !strings.HasSuffix(line, tt.wantSuffix) || strings.Count(line, "\n") != strings.Count(tt.wantSuffix, "\n") {
+				t.Errorf("expected the entry to end with %q, got %q", tt.wantSuffix, line)
+			}
+		})
+	}
+}
+
+func TestFormat_InvalidLineEnding(t *testing.T) {
+	cfg := log.Config{Service: "payments", Env: "dev", Level: log.InfoLevel, Output: log.OutputStdout, LineEnding: "\r"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "line ending") {
+		t.Errorf("expected a line ending error, got %v", err)
+	}
+}
+
+func TestFormat_DatadogSchema(t *testing.T) {
+	defer log.SetExit(func(int) {})()
+	logger, entries := newTestLogger(t, log.Config{Service: "payments", Env: "production", Schema: log.SchemaDatadog})
+
+	logger.Debug("req-1", "debug", nil)
+	logger.Info("req-1", "info", nil)
+	logger.Warn("req-1", "warn", nil)
+	logger.Error("req-1", "error", nil)
+	logger.Fatal("req-1", "fatal", nil)
+
+	got := entries()
+	wantStatus := []string{"debug", "info", "warning", "error", "critical"}
+	for i, logEntry := range got {
+		if logEntry["status"] != wantStatus[i] {
+			t.Errorf("entry %d: expected status=%s, got %v", i, wantStatus[i], logEntry["status"])
+		}
+		if logEntry["service"] != "payments" || logEntry["dd.env"] != "production" || logEntry["message"] == nil || logEntry["timestamp"] == nil {
+			t.Errorf("entry %d: expected Datadog reserved attributes, got %v", i, logEntry)
+		}
+		for _, key := range []string{"level", "env"} {
+			if _, exists := logEntry[key]; exists {
+				t.Errorf("entry %d: expected no %q key, got %v", i, key, logEntry[key])
+			}
+		}
+	}
+}
+
+func TestFormat_DatadogTrace(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{Schema: log.SchemaDatadog})
+
+	log.FromContext(log.IntoContext(context.Background(), logger.WithDatadogTrace(1234, 5678))).Info("req-1", "traced", nil)
+
+	ctx := log.ContextWithDatadogTrace(log.ContextWithTraceID(context.Background(), "req-2"), 4321, 8765)
+	logger.InfoCtx(ctx, "from context", nil)
+	logger.WithContext(ctx).Info("", "bound from context", nil)
+
+	got := entries()
+	for i, want := range [][2]string{{"1234", "5678"}, {"4321", "8765"}, {"4321", "8765"}} {
+		if got[i]["dd.trace_id"] != want[0] || got[i]["dd.span_id"] != want[1] {
+			t.Errorf("entry %d: expected dd.trace_id=%s and dd.span_id=%s, got %v", i, want[0], want[1], got[i])
+		}
+	}
+	if got[2]["trace_id"] != "req-2" {
+		t.Errorf("expected the context traceId too, got %v", got[2]["trace_id"])
+	}
+
+	plain, plainEntries := newTestLogger(t, log.Config{})
+	plain.InfoCtx(ctx, "default schema", nil)
+	if _, exists := plainEntries()[0]["dd.trace_id"]; exists {
+		t.Errorf("expected the default schema to ignore the Datadog span, got %v", plainEntries()[0])
+	}
+}
+
+func TestFormat_DatadogReader(t *testing.T) {
+	line := `{"status":"warning","timestamp":"2025-01-15T10:30:00.000Z","message":"m","service":"s","dd.env":"production","trace_id":"t","metadata":null}`
+	entry, err := log.NewReader(strings.NewReader(line)).Next()
+	if err != nil {
+		t.Fatalf("Next returned error: %v", err)
+	}
+	if entry.Level != log.WarnLevel || entry.Env != "production" || len(entry.Fields) != 0 {
+		t.Errorf("expected Datadog keys mapped to Entry fields, got %+v", entry)
+	}
+}
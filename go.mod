@@ -4,7 +4,11 @@ go 1.25.0
 
 require (
 	go.uber.org/zap v1.27.1
+	golang.org/x/term v0.44.0
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1
 )
 
-require go.uber.org/multierr v1.10.0 // indirect
+require (
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/sys v0.46.0 // indirect
+)
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// StartHeartbeat logs msg at info level every interval until the returned stop
+// function is called. Each entry has a 'beat' counter starting at 1 and
+// 'uptime_ms', the time since the logger was created, giving liveness monitors a
+// standard periodic marker. stop waits for an in-progress beat to finish and
+// may be called more than once.
+//
+// Example:
+//
+//	stop := logger.StartHeartbeat("heartbeat", "alive", 30*time.Second)
+//	defer stop()
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID),
+// or if interval is not positive.
+func (l *Logger) StartHeartbeat(traceId string, msg string, interval time.Duration) (stop func()) {
+	traceId = l.resolveTraceID(traceId)
+	if interval <= 0 {
+		panic("log: heartbeat interval must be positive")
+	}
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for beat := int64(1); ; beat++ {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fields := []Field{Int64("beat", beat)}
+				if !l.includeUptime { // Otherwise already added to every entry
+					fields = append(fields, Int64("uptime_ms", l.clock.Now().Sub(l.created).Milliseconds()))
+				}
+				l.log(zapcore.InfoLevel, 1, time.Time{}, traceId, msg, nil, fields)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_StartHeartbeat(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	stop := logger.StartHeartbeat("heartbeat", "alive", 5*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	stop()
+	stop() // Safe to call twice
+
+	got := entries()
+	if len(got) < 2 {
+		t.Fatalf("expected at least 2 heartbeats, got %d", len(got))
+	}
+	for i, logEntry := range got {
+		if logEntry["beat"] != float64(i+1) {
+			t.Errorf("entry %d: expected beat=%d, got %v", i, i+1, logEntry["beat"])
+		}
+		if uptime, ok := logEntry["uptime_ms"].(float64); !ok || uptime < 0 {
+			t.Errorf("entry %d: expected uptime_ms, got %v", i, logEntry["uptime_ms"])
+		}
+		if logEntry["level"] != "info" || logEntry["message"] != "alive" || logEntry["trace_id"] != "heartbeat" {
+			t.Errorf("entry %d: unexpected entry %v", i, logEntry)
+		}
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if extra := entries(); len(extra) != 0 {
+		t.Errorf("expected no heartbeats after stop, got %d", len(extra))
+	}
+}
@@ -0,0 +1,49 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+// newTestLogger creates a logger that writes to an in-memory channel.
+// Service, Env, and Level default to test values when empty. The returned
+// function decodes every entry written since the previous call.
+func newTestLogger(t *testing.T, cfg log.Config) (*log.Logger, func() []map[string]any) {
+	t.Helper()
+
+	if cfg.Service == "" {
+		cfg.Service = "test-service"
+	}
+	if cfg.Env == "" {
+		cfg.Env = "dev"
+	}
+	if cfg.Level == "" {
+		cfg.Level = log.DebugLevel
+	}
+
+	ch := make(chan []byte, 1024)
+	logger, err := log.NewChannelLogger(cfg, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	return logger, func() []map[string]any {
+		t.Helper()
+		var entries []map[string]any
+		for {
+			select {
+			case line := <-ch:
+				var logEntry map[string]any
+				if err := json.Unmarshal(bytes.TrimSpace(line), &logEntry); err != nil {
+					t.Fatalf("log output is not valid JSON: %v", err)
+				}
+				entries = append(entries, logEntry)
+			default:
+				return entries
+			}
+		}
+	}
+}
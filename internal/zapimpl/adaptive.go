@@ -0,0 +1,114 @@
+package zapimpl
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// AdaptiveSampling configures the adaptive sampler; see newAdaptiveCore.
+type AdaptiveSampling struct {
+	LatencyThreshold time.Duration
+	QueueThreshold   float64
+	Interval         time.Duration
+	MaxRatio         int
+}
+
+// adaptiveController decides which debug and info entries to keep based on
+// how the output is coping. Every interval it looks at the average write
+// latency since the last evaluation and, for async output, how full the queue
+// is. If either is above its threshold, the output is under pressure and the
+// ratio of entries dropped doubles, up to keeping one in MaxRatio; otherwise
+// it halves, back down to keeping every entry. Doubling and halving react
+// quickly to sudden load without oscillating on a single slow write.
+type adaptiveController struct {
+	cfg       AdaptiveSampling
+	queueFill func() float64 // nil unless the output is async
+	stats     *Stats
+
+	ratio    atomic.Int64 // Keep one entry in ratio; 1 keeps everything
+	seen     atomic.Uint64
+	nextEval atomic.Int64 // UnixNano of the next evaluation
+
+	// Updated by the timed write syncer
+	writes       atomic.Uint64
+	writeLatency atomic.Int64 // Total nanoseconds since the last evaluation
+}
+
+func newAdaptiveController(cfg AdaptiveSampling, queueFill func() float64, stats *Stats) *adaptiveController {
+	c := &adaptiveController{cfg: cfg, queueFill: queueFill, stats: stats}
+	c.ratio.Store(1)
+	c.nextEval.Store(time.Now().Add(cfg.Interval).UnixNano())
+	return c
+}
+
+// admit reports whether an entry should be written, evaluating the output's
+// health first when an interval has passed.
+func (c *adaptiveController) admit() bool {
+	now := time.Now().UnixNano()
+	if next := c.nextEval.Load(); now >= next && c.nextEval.CompareAndSwap(next, now+int64(c.cfg.Interval)) {
+		c.evaluate()
+	}
+
+	ratio := c.ratio.Load()
+	if ratio == 1 || c.seen.Add(1)%uint64(ratio) == 0 {
+		return true
+	}
+	c.stats.AdaptiveDroppedEntries.Add(1)
+	return false
+}
+
+// evaluate adjusts the ratio from the writes since the last evaluation.
+func (c *adaptiveController) evaluate() {
+	writes := c.writes.Swap(0)
+	latency := time.Duration(c.writeLatency.Swap(0))
+
+	pressured := writes > 0 && latency/time.Duration(writes) > c.cfg.LatencyThreshold
+	if c.queueFill != nil && c.queueFill() > c.cfg.QueueThreshold {
+		pressured = true
+	}
+
+	ratio := c.ratio.Load()
+	if pressured {
+		ratio = min(ratio*2, int64(c.cfg.MaxRatio))
+	} else {
+		ratio = max(ratio/2, 1)
+	}
+	c.ratio.Store(ratio)
+}
+
+// adaptiveCore drops debug and info entries as decided by an
+// adaptiveController. Warn and above are always written.
+type adaptiveCore struct {
+	zapcore.Core
+	ctl *adaptiveController
+}
+
+func (c *adaptiveCore) With(fields []zapcore.Field) zapcore.Core {
+	return &adaptiveCore{Core: c.Core.With(fields), ctl: c.ctl}
+}
+
+func (c *adaptiveCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	if ent.Level < zapcore.WarnLevel && !c.ctl.admit() {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// timedWriteSyncer reports the latency of every write to an adaptiveController.
+type timedWriteSyncer struct {
+	zapcore.WriteSyncer
+	ctl *adaptiveController
+}
+
+func (w *timedWriteSyncer) Write(p []byte) (int, error) {
+	start := time.Now()
+	n, err := w.WriteSyncer.Write(p)
+	w.ctl.writeLatency.Add(int64(time.Since(start)))
+	w.ctl.writes.Add(1)
+	return n, err
+}
@@ -0,0 +1,238 @@
+package zapimpl
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// asyncCore hands entries to a pool of workers that encode them, while a single
+// writer goroutine writes the encoded entries in the order they were logged.
+// This keeps encoding and IO latency off the calling goroutine without
+// reordering entries.
+type asyncCore struct {
+	zapcore.LevelEnabler
+	enc   zapcore.Encoder
+	queue *asyncQueue
+}
+
+// maxFailedEntries bounds how many unwritten entries are kept for Drain.
+const maxFailedEntries = 10000
+
+func newAsyncCore(enc zapcore.Encoder, ws, errorOutput zapcore.WriteSyncer, enab zapcore.LevelEnabler, workers, queueSize int) *asyncCore {
+	return &asyncCore{
+		LevelEnabler: enab,
+		enc:          enc,
+		queue:        newAsyncQueue(ws, errorOutput, workers, queueSize),
+	}
+}
+
+func (c *asyncCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := c.enc.Clone()
+	for i := range fields {
+		fields[i].AddTo(clone)
+	}
+	return &asyncCore{LevelEnabler: c.LevelEnabler, enc: clone, queue: c.queue}
+}
+
+func (c *asyncCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *asyncCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	// The caller may reuse its slice once Write returns.
+	fields = append([]zapcore.Field(nil), fields...)
+	if err := c.queue.enqueue(c.enc, ent, fields); err != nil {
+		return err
+	}
+	if ent.Level > zapcore.ErrorLevel {
+		// The process may be about to exit; make sure this entry is on disk.
+		return c.Sync()
+	}
+	return nil
+}
+
+func (c *asyncCore) Sync() error {
+	return c.queue.sync()
+}
+
+// asyncJob is a single entry travelling through the queue.
+type asyncJob struct {
+	enc    zapcore.Encoder
+	ent    zapcore.Entry
+	fields []zapcore.Field
+	buf    *buffer.Buffer
+	err    error
+	done   chan struct{}
+}
+
+// asyncQueue is shared by an asyncCore and all of its With clones.
+type asyncQueue struct {
+	ws          zapcore.WriteSyncer
+	errorOutput zapcore.WriteSyncer
+
+	jobs  chan *asyncJob // consumed by the encoding workers, in any order
+	order chan *asyncJob // consumed by the writer, in logging order
+
+	// mu guards closed against concurrent enqueues.
+	mu     sync.RWMutex
+	closed bool
+
+	// progress tracks enqueued vs written entries so sync can wait for the
+	// entries logged before it was called.
+	progress sync.Mutex
+	written  *sync.Cond
+	enqueued uint64
+	flushed  uint64
+
+	// failed holds entries the writer could not write, for drain.
+	failedMu sync.Mutex
+	failed   [][]byte
+
+	workers sync.WaitGroup
+	writer  sync.WaitGroup
+}
+
+func newAsyncQueue(ws, errorOutput zapcore.WriteSyncer, workers, queueSize int) *asyncQueue {
+	q := &asyncQueue{
+		ws:          ws,
+		errorOutput: errorOutput,
+		jobs:        make(chan *asyncJob, queueSize),
+		order:       make(chan *asyncJob, queueSize),
+	}
+	q.written = sync.NewCond(&q.progress)
+
+	for i := 0; i < workers; i++ {
+		q.workers.Add(1)
+		go q.encode()
+	}
+	q.writer.Add(1)
+	go q.write()
+
+	return q
+}
+
+func (q *asyncQueue) enqueue(enc zapcore.Encoder, ent zapcore.Entry, fields []zapcore.Field) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		// After Close, fall back to writing on the calling goroutine.
+		buf, err := enc.EncodeEntry(ent, fields)
+		if err != nil {
+			return err
+		}
+		_, err = q.ws.Write(buf.Bytes())
+		buf.Free()
+		return err
+	}
+
+	job := &asyncJob{enc: enc, ent: ent, fields: fields, done: make(chan struct{})}
+
+	q.progress.Lock()
+	q.enqueued++
+	q.progress.Unlock()
+
+	// The writer must see jobs in logging order; workers may pick them up in any order.
+	q.order <- job
+	q.jobs <- job
+	return nil
+}
+
+func (q *asyncQueue) encode() {
+	defer q.workers.Done()
+	for job := range q.jobs {
+		job.buf, job.err = job.enc.EncodeEntry(job.ent, job.fields)
+		close(job.done)
+	}
+}
+
+func (q *asyncQueue) write() {
+	defer q.writer.Done()
+	for job := range q.order {
+		<-job.done
+		if job.err != nil {
+			q.reportError(job.ent, job.err)
+		} else {
+			if _, err := q.ws.Write(job.buf.Bytes()); err != nil {
+				q.reportError(job.ent, err)
+				q.keepFailed(job.buf.Bytes())
+			}
+			job.buf.Free()
+		}
+
+		q.progress.Lock()
+		q.flushed++
+		q.written.Broadcast()
+		q.progress.Unlock()
+	}
+}
+
+// reportError writes a diagnostic for an entry that could not be encoded or
+// written, mirroring what zap does for synchronous cores.
+func (q *asyncQueue) reportError(ent zapcore.Entry, err error) {
+	_, _ = fmt.Fprintf(q.errorOutput, "%v write error: %v\n", ent.Time, err)
+	_ = q.errorOutput.Sync()
+}
+
+// keepFailed retains a copy of an unwritten entry for drain.
+func (q *asyncQueue) keepFailed(entry []byte) {
+	q.failedMu.Lock()
+	defer q.failedMu.Unlock()
+	if len(q.failed) < maxFailedEntries {
+		q.failed = append(q.failed, append([]byte(nil), entry...))
+	}
+}
+
+// drain waits for queued entries to be written and returns, and forgets, the
+// entries that could not be written.
+func (q *asyncQueue) drain() ([][]byte, error) {
+	err := q.sync()
+
+	q.failedMu.Lock()
+	defer q.failedMu.Unlock()
+	failed := q.failed
+	q.failed = nil
+	return failed, err
+}
+
+// sync waits until every entry enqueued before the call has been written,
+// then syncs the underlying writer.
+func (q *asyncQueue) sync() error {
+	q.progress.Lock()
+	target := q.enqueued
+	for q.flushed < target {
+		q.written.Wait()
+	}
+	q.progress.Unlock()
+
+	return q.ws.Sync()
+}
+
+// fill returns the fraction of the queue in use, from 0 to 1.
+func (q *asyncQueue) fill() float64 {
+	return float64(len(q.order)) / float64(cap(q.order))
+}
+
+// close drains the queue and stops the workers. Entries logged afterwards are
+// written synchronously.
+func (q *asyncQueue) close() error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	close(q.jobs)
+	close(q.order)
+	q.mu.Unlock()
+
+	q.workers.Wait()
+	q.writer.Wait()
+	return q.ws.Sync()
+}
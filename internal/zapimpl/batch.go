@@ -0,0 +1,69 @@
+package zapimpl
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/glennprays/log/batch"
+	"go.uber.org/zap/zapcore"
+)
+
+// batchWriteSyncer writes the entries of each window as one payload: a JSON
+// array of the entries, followed by lineEnding, or, for lines, the entries
+// one after another.
+type batchWriteSyncer struct {
+	ws         zapcore.WriteSyncer
+	batcher    *batch.Batcher[[]byte]
+	lines      bool
+	lineEnding string
+}
+
+func newBatchWriteSyncer(ws zapcore.WriteSyncer, window time.Duration, maxEntries int, lines bool, lineEnding string) *batchWriteSyncer {
+	w := &batchWriteSyncer{ws: ws, lines: lines, lineEnding: lineEnding}
+	w.batcher = batch.New(batch.Options{Window: window, MaxItems: maxEntries}, w.send)
+	return w
+}
+
+// Write buffers one entry. zap reuses p once Write returns, so it is copied.
+func (w *batchWriteSyncer) Write(p []byte) (int, error) {
+	return len(p), w.batcher.Add(bytes.Clone(p))
+}
+
+// Sync writes the buffered entries and syncs the output.
+func (w *batchWriteSyncer) Sync() error {
+	return errors.Join(w.batcher.Flush(), w.ws.Sync())
+}
+
+// Close writes the buffered entries and stops writing on the window.
+func (w *batchWriteSyncer) Close() error {
+	return w.batcher.Close()
+}
+
+func (w *batchWriteSyncer) send(batch [][]byte) (int, error) {
+	buf := framePool.Get()
+	defer buf.Free()
+
+	if w.lines {
+		for _, entry := range batch {
+			buf.AppendBytes(entry)
+		}
+	} else {
+		buf.AppendByte('[')
+		for i, entry := range batch {
+			if i > 0 {
+				buf.AppendByte(',')
+			}
+			entry = bytes.TrimSuffix(entry, []byte("\n"))
+			entry = bytes.TrimSuffix(entry, []byte("\r"))
+			buf.AppendBytes(entry)
+		}
+		buf.AppendByte(']')
+		buf.AppendString(w.lineEnding)
+	}
+
+	if _, err := w.ws.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(batch), nil
+}
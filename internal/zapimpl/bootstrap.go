@@ -0,0 +1,157 @@
+package zapimpl
+
+import (
+	"errors"
+	"os"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// MaxBootstrapEntries is how many entries a BootstrapCore buffers; later
+// entries are dropped and counted.
+const MaxBootstrapEntries = 10000
+
+// bootstrapBuffer holds the entries of a BootstrapCore and its children until
+// they are adopted by a real core.
+type bootstrapBuffer struct {
+	mu      sync.Mutex
+	entries []bufferedEntry
+	dropped int
+	dumped  int          // How many of entries dumpLocked has written
+	target  zapcore.Core // nil until adopted
+}
+
+// bufferedEntry is an entry with all of its fields, including those of the
+// core it was written to.
+type bufferedEntry struct {
+	ent    zapcore.Entry
+	fields []zapcore.Field
+}
+
+// BootstrapCore is a zapcore.Core that buffers entries in memory until Adopt
+// replays them through a real core; after that it forwards entries to it.
+// Children created with With share the buffer.
+type BootstrapCore struct {
+	buf    *bootstrapBuffer
+	fields []zapcore.Field
+}
+
+// NewBootstrapCore returns an empty BootstrapCore that enables every level.
+func NewBootstrapCore() *BootstrapCore {
+	return &BootstrapCore{buf: &bootstrapBuffer{}}
+}
+
+func (c *BootstrapCore) Enabled(level zapcore.Level) bool {
+	c.buf.mu.Lock()
+	target := c.buf.target
+	c.buf.mu.Unlock()
+	return target == nil || target.Enabled(level)
+}
+
+func (c *BootstrapCore) With(fields []zapcore.Field) zapcore.Core {
+	return &BootstrapCore{buf: c.buf, fields: append(c.fields[:len(c.fields):len(c.fields)], fields...)}
+}
+
+func (c *BootstrapCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *BootstrapCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(append(all, c.fields...), fields...)
+
+	target, err := c.buf.add(ent, all)
+	if target != nil {
+		// Written outside the lock, so a slow target doesn't block other writers
+		writeTo(target, ent, all)
+	}
+	return err
+}
+
+// add buffers an entry, or returns the target to write it to once adopted.
+func (b *bootstrapBuffer) add(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Core, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.target != nil {
+		return b.target, nil
+	}
+	if ent.Level >= zapcore.DPanicLevel {
+		// The process may exit before the buffer is adopted. The entries stay
+		// buffered in case the panic is recovered and Adopt still follows.
+		b.entries = append(b.entries, bufferedEntry{ent: ent, fields: fields})
+		return nil, b.dumpLocked()
+	}
+	if len(b.entries) >= MaxBootstrapEntries {
+		b.dropped++
+		return nil, nil
+	}
+	b.entries = append(b.entries, bufferedEntry{ent: ent, fields: fields})
+	return nil, nil
+}
+
+func (c *BootstrapCore) Sync() error {
+	c.buf.mu.Lock()
+	target := c.buf.target
+	c.buf.mu.Unlock()
+	if target == nil {
+		return nil
+	}
+	return target.Sync()
+}
+
+// Adopt replays the buffered entries through target, keeping their original
+// timestamps, and forwards all later entries to it. It returns the number of
+// entries dropped because the buffer was full. Adopt fails if the core has
+// already been adopted.
+func (c *BootstrapCore) Adopt(target zapcore.Core) (dropped int, err error) {
+	c.buf.mu.Lock()
+	defer c.buf.mu.Unlock()
+	if c.buf.target != nil {
+		return 0, errors.New("bootstrap logger already adopted")
+	}
+	for _, buffered := range c.buf.entries {
+		writeTo(target, buffered.ent, buffered.fields)
+	}
+	c.buf.target = target
+	c.buf.entries = nil
+	return c.buf.dropped, nil
+}
+
+// dumpLocked writes the buffered entries not written by an earlier call to
+// stderr as JSON, for when the process exits before they are adopted.
+func (b *bootstrapBuffer) dumpLocked() error {
+	encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		TimeKey:        "timestamp",
+		LevelKey:       "level",
+		MessageKey:     "message",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+	})
+	var errs []error
+	for _, buffered := range b.entries[b.dumped:] {
+		line, err := encoder.EncodeEntry(buffered.ent, buffered.fields)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		_, err = os.Stderr.Write(line.Bytes())
+		line.Free()
+		errs = append(errs, err)
+	}
+	b.dumped = len(b.entries)
+	return errors.Join(errs...)
+}
+
+// writeTo writes an entry through core, applying the core's level and sampling.
+func writeTo(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) {
+	if ce := core.Check(ent, nil); ce != nil {
+		ce.Write(fields...)
+	}
+}
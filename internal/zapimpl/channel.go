@@ -0,0 +1,37 @@
+package zapimpl
+
+// channelWriteSyncer sends each encoded entry to a channel.
+type channelWriteSyncer struct {
+	ch    chan<- []byte
+	block bool
+	stats *Stats
+}
+
+func newChannelWriteSyncer(ch chan<- []byte, block bool, stats *Stats) *channelWriteSyncer {
+	return &channelWriteSyncer{ch: ch, block: block, stats: stats}
+}
+
+// Write sends a copy of p to the channel. When the channel is full, the entry
+// is either dropped and counted or the call blocks until there is room.
+func (w *channelWriteSyncer) Write(p []byte) (int, error) {
+	// zap reuses the buffer once Write returns, so the consumer gets its own copy.
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	if w.block {
+		w.ch <- entry
+		return len(p), nil
+	}
+
+	select {
+	case w.ch <- entry:
+	default:
+		w.stats.DroppedEntries.Add(1)
+	}
+	return len(p), nil
+}
+
+// Sync is a no-op; entries are handed off as soon as they are written.
+func (w *channelWriteSyncer) Sync() error {
+	return nil
+}
@@ -0,0 +1,146 @@
+package zapimpl
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ChecksumTrailer is the final line written to a checksummed file: the SHA-256
+// of every preceding byte and the number of entries before it. Trailers of
+// earlier sessions are not entries; a CSV header row is.
+type ChecksumTrailer struct {
+	Checksum string `json:"checksum"` // "sha256:<hex>"
+	Entries  int64  `json:"entries"`
+}
+
+// ChecksumPrefix starts the value of ChecksumTrailer.Checksum.
+const ChecksumPrefix = "sha256:"
+
+// ParseChecksumTrailer parses line, without its line ending, as a
+// ChecksumTrailer. It reports false for any other line, including entries
+// that happen to have a "checksum" field.
+func ParseChecksumTrailer(line []byte) (ChecksumTrailer, bool) {
+	var trailer ChecksumTrailer
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&trailer); err != nil || !strings.HasPrefix(trailer.Checksum, ChecksumPrefix) {
+		return ChecksumTrailer{}, false
+	}
+	return trailer, true
+}
+
+// CountEntries counts the complete lines in content that are not checksum
+// trailers.
+func CountEntries(content []byte) int64 {
+	var entries int64
+	for line := range bytes.Lines(content) {
+		line, complete := bytes.CutSuffix(line, []byte("\n"))
+		if _, trailer := ParseChecksumTrailer(line); complete && !trailer {
+			entries++
+		}
+	}
+	return entries
+}
+
+// checksumFile appends entries to a file without rotation while hashing
+// everything in it, and writes a ChecksumTrailer when closed. Content already
+// in the file is hashed and counted when it is opened, so the trailer covers
+// the whole file.
+type checksumFile struct {
+	mu      sync.Mutex
+	file    *os.File
+	hash    hash.Hash
+	entries int64
+	closed  bool
+}
+
+func newChecksumFile(path string) (*checksumFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checksumFile{file: file, hash: sha256.New()}
+	if err := c.hashExisting(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read existing log file: %w", err)
+	}
+	return c, nil
+}
+
+// hashExisting hashes the content already in the file and counts its entries.
+func (c *checksumFile) hashExisting() error {
+	r := bufio.NewReader(io.TeeReader(c.file, c.hash))
+	for {
+		line, err := r.ReadBytes('\n')
+		c.entries += CountEntries(line)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Write appends one entry and adds it to the checksum.
+func (c *checksumFile) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0, os.ErrClosed
+	}
+	n, err := c.file.Write(p)
+	c.hash.Write(p[:n])
+	c.entries += int64(bytes.Count(p[:n], []byte("\n")))
+	return n, err
+}
+
+func (c *checksumFile) Sync() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	return c.file.Sync()
+}
+
+// Close writes the trailer and closes the file. Later writes fail.
+func (c *checksumFile) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	trailer, err := json.Marshal(ChecksumTrailer{
+		Checksum: ChecksumPrefix + hex.EncodeToString(c.hash.Sum(nil)),
+		Entries:  c.entries,
+	})
+	if err != nil {
+		c.file.Close()
+		return err
+	}
+	if _, err := c.file.Write(append(trailer, '\n')); err != nil {
+		c.file.Close()
+		return err
+	}
+	return c.file.Close()
+}
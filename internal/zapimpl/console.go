@@ -0,0 +1,15 @@
+package zapimpl
+
+import "go.uber.org/zap/zapcore"
+
+// newConsoleEncoder creates a human-readable encoder based on cfg, with
+// colored levels if color is true:
+//
+//	2025-01-15T10:30:00.000Z	INFO	user logged in	{"service": "api", "trace_id": "abc"}
+func newConsoleEncoder(cfg zapcore.EncoderConfig, color bool) zapcore.Encoder {
+	cfg.EncodeLevel = zapcore.CapitalLevelEncoder
+	if color {
+		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+	return zapcore.NewConsoleEncoder(cfg)
+}
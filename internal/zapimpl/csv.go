@@ -0,0 +1,200 @@
+package zapimpl
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// CSVOtherFields is the column that holds, as a JSON object, every field
+// without a column of its own.
+const CSVOtherFields = "other_fields"
+
+// csvEncoder encodes entries as CSV rows with a fixed column order. The
+// header row is not part of any entry; writeHeader or csvFile writes it to the
+// output when it is opened, so it is never dropped with an entry. Like cefEncoder, it
+// accumulates context fields in a JSON encoder and rewrites each encoded
+// entry, so every zap field type is supported. Fields without a column are
+// dropped unless there is a CSVOtherFields column.
+type csvEncoder struct {
+	zapcore.Encoder
+	columns    []string
+	other      bool // Whether there is a CSVOtherFields column
+	lineEnding string
+}
+
+func newCSVEncoder(cfg zapcore.EncoderConfig, columns []string) zapcore.Encoder {
+	lineEnding := cfg.LineEnding
+	cfg.LineEnding = "\n"
+	return &csvEncoder{
+		Encoder:    zapcore.NewJSONEncoder(cfg),
+		columns:    columns,
+		other:      slices.Contains(columns, CSVOtherFields),
+		lineEnding: lineEnding,
+	}
+}
+
+func (e *csvEncoder) Clone() zapcore.Encoder {
+	clone := *e
+	clone.Encoder = e.Encoder.Clone()
+	return &clone
+}
+
+func (e *csvEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	encoded, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer encoded.Free()
+
+	jsonFields, err := decodeJSONEntry(encoded.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(jsonFields))
+	var other bytes.Buffer
+	for _, f := range jsonFields {
+		if !e.hasColumn(f.key) {
+			if !e.other {
+				continue
+			}
+			if other.Len() == 0 {
+				other.WriteByte('{')
+			} else {
+				other.WriteByte(',')
+			}
+			key, _ := json.Marshal(f.key)
+			other.Write(key)
+			other.WriteByte(':')
+			other.Write(f.value)
+			continue
+		}
+		if string(f.value) != "null" {
+			values[f.key] = f.text()
+		}
+	}
+	if other.Len() > 0 {
+		other.WriteByte('}')
+		values[CSVOtherFields] = other.String()
+	}
+
+	row := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		row[i] = values[column]
+	}
+
+	buf := pool.Get()
+	e.writeRow(buf, row)
+	return buf, nil
+}
+
+// header returns the header row.
+func (e *csvEncoder) header() []byte {
+	buf := pool.Get()
+	defer buf.Free()
+	e.writeRow(buf, e.columns)
+	return bytes.Clone(buf.Bytes())
+}
+
+// writeHeader writes the header row to ws.
+func (e *csvEncoder) writeHeader(ws zapcore.WriteSyncer) error {
+	if _, err := ws.Write(e.header()); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	return nil
+}
+
+// csvFile writes CSV rows to a rotating file, with the header at the start of
+// each file: when the file is empty at open and after each rotation. It
+// rotates the file itself just before lumberjack would, so the header and the
+// row that caused the rotation both land in the new file.
+type csvFile struct {
+	mu     sync.Mutex
+	file   *lumberjack.Logger
+	header []byte
+	size   int64 // Bytes in the current file
+	max    int64 // lumberjack's size limit
+}
+
+// newCSVFile opens file for CSV rows, writing the header if the file is
+// missing or empty.
+func newCSVFile(file *lumberjack.Logger, header []byte) (*csvFile, error) {
+	f := &csvFile{file: file, header: header, max: lumberjackMaxSize(file)}
+	if info, err := os.Stat(file.Filename); err == nil && info.Size() > 0 {
+		f.size = info.Size() // Appending to a file that already has its header
+		return f, nil
+	}
+	if _, err := file.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write csv header: %w", err)
+	}
+	f.size = int64(len(header))
+	return f, nil
+}
+
+func (f *csvFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.size+int64(len(p)) < f.max {
+		n, err := f.file.Write(p)
+		f.size += int64(n)
+		return n, err
+	}
+	if err := f.file.Rotate(); err != nil {
+		return 0, err
+	}
+	f.size = 0
+	row := append(bytes.Clone(f.header), p...)
+	n, err := f.file.Write(row)
+	f.size += int64(n)
+	return max(n-len(f.header), 0), err
+}
+
+func (f *csvFile) Sync() error {
+	return nil
+}
+
+// isEmptyFile reports whether the file at path is missing or empty.
+func isEmptyFile(path string) bool {
+	info, err := os.Stat(path)
+	return err != nil || info.Size() == 0
+}
+
+// lumberjackMaxSize returns the size in bytes at which l rotates.
+func lumberjackMaxSize(l *lumberjack.Logger) int64 {
+	const megabyte = 1024 * 1024
+	if l.MaxSize == 0 {
+		return 100 * megabyte // lumberjack's default
+	}
+	return int64(l.MaxSize) * megabyte
+}
+
+// hasColumn reports whether key has a column of its own.
+func (e *csvEncoder) hasColumn(key string) bool {
+	for _, column := range e.columns {
+		if column == key && column != CSVOtherFields {
+			return true
+		}
+	}
+	return false
+}
+
+// writeRow appends row to buf as one CSV record ending with the line ending.
+// Values containing commas, quotes, or line breaks are quoted.
+func (e *csvEncoder) writeRow(buf *buffer.Buffer, row []string) {
+	var record bytes.Buffer
+	w := csv.NewWriter(&record)
+	_ = w.Write(row) // Writes to a bytes.Buffer don't fail
+	w.Flush()
+	buf.Write(bytes.TrimSuffix(record.Bytes(), []byte("\n")))
+	buf.AppendString(e.lineEnding)
+}
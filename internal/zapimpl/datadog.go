@@ -0,0 +1,25 @@
+package zapimpl
+
+import "go.uber.org/zap/zapcore"
+
+// datadogLevelEncoder writes levels as Datadog status values.
+func datadogLevelEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	switch level {
+	case zapcore.DebugLevel:
+		enc.AppendString("debug")
+	case zapcore.InfoLevel:
+		enc.AppendString("info")
+	case zapcore.WarnLevel:
+		enc.AppendString("warning")
+	case zapcore.ErrorLevel:
+		enc.AppendString("error")
+	default:
+		enc.AppendString("critical")
+	}
+}
+
+// datadogKeys maps the default schema's names to their Datadog equivalents.
+var datadogKeys = map[string]string{
+	"level": "status",
+	"env":   "dd.env",
+}
@@ -0,0 +1,28 @@
+package zapimpl
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// removeLogFiles removes the log file at path and the backups lumberjack
+// rotated it into, which are named <name>-<timestamp><ext> alongside it.
+func removeLogFiles(path string) error {
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(path, ext)
+	backups, err := filepath.Glob(prefix + "-????-??-??T??-??-??.???" + ext)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, file := range append(backups, path) {
+		if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+package zapimpl
+
+import (
+	"bytes"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// framePool holds the buffers framedWriteSyncer assembles lines in.
+var framePool = buffer.NewPool()
+
+// framedWriteSyncer surrounds each encoded entry with a prefix and a suffix,
+// keeping the entry on one line: the suffix goes before the line ending, if
+// any.
+type framedWriteSyncer struct {
+	zapcore.WriteSyncer
+	prefix string
+	suffix string
+}
+
+func newFramedWriteSyncer(ws zapcore.WriteSyncer, prefix, suffix string) *framedWriteSyncer {
+	return &framedWriteSyncer{WriteSyncer: ws, prefix: prefix, suffix: suffix}
+}
+
+// Write writes one framed entry. zap calls Write once per complete entry, so
+// the frame never splits an entry.
+func (w *framedWriteSyncer) Write(p []byte) (int, error) {
+	entry, ending := p, []byte(nil)
+	for _, lineEnding := range []string{"\r\n", "\n"} {
+		if bytes.HasSuffix(entry, []byte(lineEnding)) {
+			entry, ending = entry[:len(entry)-len(lineEnding)], entry[len(entry)-len(lineEnding):]
+			break
+		}
+	}
+
+	buf := framePool.Get()
+	defer buf.Free()
+	buf.AppendString(w.prefix)
+	buf.AppendBytes(entry)
+	buf.AppendString(w.suffix)
+	buf.AppendBytes(ending)
+
+	if _, err := w.WriteSyncer.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// noLineEndingEncoder removes the line ending zap's JSON encoder always
+// writes, which it cannot be configured to omit.
+type noLineEndingEncoder struct {
+	zapcore.Encoder
+}
+
+func (e noLineEndingEncoder) Clone() zapcore.Encoder {
+	return noLineEndingEncoder{e.Encoder.Clone()}
+}
+
+func (e noLineEndingEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	buf.TrimNewline()
+	return buf, nil
+}
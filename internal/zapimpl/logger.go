@@ -1,16 +1,240 @@
 package zapimpl
 
 import (
+	"cmp"
+	"errors"
+	"io"
+	"maps"
 	"os"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+// Options holds the settings used by BuildLogger.
+type Options struct {
+	Service string
+	Env     string
+
+	// Level decides which entries are written; a zap.AtomicLevel allows
+	// changing it after the logger is built.
+	Level zapcore.LevelEnabler
+
+	// Format is "json" (default), "cef", "csv", or "console"; CEF holds the
+	// CEF header values and CSVColumns the CSV column order. Color colors the
+	// levels of console entries.
+	Format     string
+	Color      bool
+	CEF        CEFOptions
+	CSVColumns []string
+
+	// Schema is "" for the default key layout or "datadog" for Datadog's
+	// reserved attributes.
+	Schema string
+
+	// SortFields orders JSON fields as reserved fields first, then alphabetically.
+	// MessageFirst moves message, level, and timestamp to the front, in that order.
+	SortFields   bool
+	MessageFirst bool
+
+	// TimeUTC converts timestamps to UTC before encoding them.
+	TimeUTC bool
+
+	// Now returns the timestamp for new entries; nil means time.Now.
+	Now func() time.Time
+
+	// ServiceKey and EnvKey rename the service and env fields; empty keeps
+	// the schema's names. Version is added as VersionKey ("version" if empty)
+	// after env when non-empty.
+	ServiceKey string
+	EnvKey     string
+	Version    string
+	VersionKey string
+
+	// SchemaVersion is added as a default field when non-empty.
+	SchemaVersion string
+
+	// Fields are added as default fields after service, env, and schema_version.
+	Fields []zap.Field
+
+	// Writer, when set, is used as the output instead of OutputType.
+	// If it implements io.Closer it is closed with the pipeline.
+	Writer zapcore.WriteSyncer
+
+	OutputType string
+	FilePath   string
+
+	// Checksum writes the file without rotation and appends a
+	// ChecksumTrailer when the pipeline is closed. Only used for file output.
+	Checksum   bool
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+
+	// EphemeralFile removes the file and its rotated backups when the
+	// pipeline is closed, unless MarkFailed was called. Only used for file
+	// output.
+	EphemeralFile bool
+
+	// Tiered, when set, moves rotated files to a compressed cold tier.
+	// Only used for file output without Checksum.
+	Tiered *TieredFiles
+
+	// Channel and BlockOnFullChannel are used when OutputType is "channel".
+	Channel            chan<- []byte
+	BlockOnFullChannel bool
+
+	// LineEnding is written after each encoded entry; empty writes none.
+	// The console mirror always ends entries with "\n".
+	LineEnding string
+
+	// LinePrefix and LineSuffix surround each encoded entry, the suffix
+	// going before the line ending.
+	LinePrefix string
+	LineSuffix string
+
+	// BatchWindow, when positive, gathers the entries written within it and
+	// writes them as one payload of at most MaxBatchEntries entries: a JSON
+	// array, or with BatchLines the entries one after another.
+	BatchWindow     time.Duration
+	MaxBatchEntries int
+	BatchLines      bool
+
+	// MaxBytesPerSecond, when positive, drops entries that would exceed this
+	// many bytes per second of output. Pipeline.Unlimited bypasses the limit.
+	MaxBytesPerSecond int
+
+	// SerializeWrites guards the write syncer with a mutex.
+	SerializeWrites bool
+
+	// SamplingInitial and SamplingThereafter enable zap's per-second sampler
+	// when SamplingInitial is positive.
+	SamplingInitial    int
+	SamplingThereafter int
+
+	// AdaptiveSampling, when set, drops a growing share of debug and info
+	// entries while the output is slow or the async queue is filling up.
+	AdaptiveSampling *AdaptiveSampling
+
+	// Async hands entries to AsyncWorkers goroutines through a queue holding
+	// up to AsyncQueueSize entries instead of writing on the calling goroutine.
+	Async          bool
+	AsyncWorkers   int
+	AsyncQueueSize int
+
+	// Console mirrors every entry to ConsoleOutput in a human-readable format,
+	// alongside the structured output. nil ConsoleOutput means os.Stderr.
+	// ConsoleColor colors console levels, for terminals.
+	Console       bool
+	ConsoleOutput io.Writer
+	ConsoleColor  bool
+
+	// Sinks are additional outputs, each with its own encoding. Like the
+	// console mirror, they are subject to sampling but not to the output
+	// settings above.
+	Sinks []SinkOptions
+
+	// ErrorOutput receives internal logger errors; nil means os.Stderr.
+	ErrorOutput io.Writer
+
+	// Stats receives counters from the output; it must not be nil.
+	Stats *Stats
+}
+
+// Pipeline is a zap logger together with the resources backing its output.
+// It is shared by a logger and all of its children.
+type Pipeline struct {
+	Logger *zap.Logger
+
+	// Unlimited writes to the same outputs as Logger, synchronously and
+	// bypassing sampling, adaptive sampling, and MaxBytesPerSecond, for
+	// entries that must not be dropped and for reporting on the limit itself.
+	Unlimited *zap.Logger
+
+	// Fields are the default fields bound to Logger and Unlimited.
+	Fields []zap.Field
+
+	async   *asyncQueue
+	closers []func() error
+	failed  *atomic.Bool // Set for EphemeralFile; shared by copies of the pipeline
+}
+
+// MarkFailed records that the process is ending abnormally, so Close keeps
+// an EphemeralFile for inspection.
+func (p *Pipeline) MarkFailed() {
+	if p.failed != nil {
+		p.failed.Store(true)
+	}
+}
+
+// Drain flushes queued entries and returns the ones that could not be written.
+// Only async pipelines retain unwritten entries; others just sync.
+func (p *Pipeline) Drain() ([][]byte, error) {
+	if p.async == nil {
+		return nil, p.Logger.Sync()
+	}
+	return p.async.drain()
+}
+
+// Tee returns a copy of the pipeline whose loggers also write to extra,
+// filtered by level. extra gets the default fields, and sees entries before
+// the sampling and limits of the pipeline's own output. The copy shares the
+// pipeline's resources.
+func (p *Pipeline) Tee(extra zapcore.Core, level zapcore.LevelEnabler) *Pipeline {
+	extra = &levelFilterCore{Core: extra.With(p.Fields), level: level}
+	tee := zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, extra)
+	})
+
+	teed := *p
+	teed.Logger = p.Logger.WithOptions(tee)
+	if p.Unlimited != nil {
+		teed.Unlimited = p.Unlimited.WithOptions(tee)
+	}
+	return &teed
+}
+
+// OnClose registers fn to run when the pipeline is closed, before the
+// resources created by BuildLogger are released.
+func (p *Pipeline) OnClose(fn func() error) {
+	p.closers = append(p.closers, fn)
+}
+
+// Close releases the pipeline's resources in reverse order of creation,
+// draining any queued entries first.
+func (p *Pipeline) Close() error {
+	var errs []error
+	for i := len(p.closers) - 1; i >= 0; i-- {
+		if err := p.closers[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// removeOnClose registers the removal of an EphemeralFile. Closers run in
+// reverse order, so it must be registered before the file's own closer.
+func (p *Pipeline) removeOnClose(opts Options) {
+	if !opts.EphemeralFile {
+		return
+	}
+	p.failed = &atomic.Bool{}
+	p.closers = append(p.closers, func() error {
+		if p.failed.Load() {
+			return nil
+		}
+		return removeLogFiles(opts.FilePath)
+	})
+}
+
 // BuildLogger creates a zap logger based on the provided configuration.
-func BuildLogger(service, env string, level zapcore.Level, outputType, filePath string, maxSizeMB, maxBackups, maxAgeDays int) (*zap.Logger, error) {
-	// Create encoder config for JSON output
+func BuildLogger(opts Options) (*Pipeline, error) {
+	pipeline := &Pipeline{}
+
+	// Create encoder config; other formats build on the JSON layout
 	encoderConfig := zapcore.EncoderConfig{
 		TimeKey:        "timestamp",
 		LevelKey:       "level",
@@ -19,44 +243,303 @@ func BuildLogger(service, env string, level zapcore.Level, outputType, filePath
 		FunctionKey:    "", // We'll add function manually
 		MessageKey:     "message",
 		StacktraceKey:  "stacktrace",
-		LineEnding:     zapcore.DefaultLineEnding,
+		LineEnding:     opts.LineEnding,
 		EncodeLevel:    zapcore.LowercaseLevelEncoder,
 		EncodeTime:     zapcore.ISO8601TimeEncoder,
 		EncodeDuration: zapcore.SecondsDurationEncoder,
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 
-	// Create JSON encoder
-	encoder := zapcore.NewJSONEncoder(encoderConfig)
+	renamed := renamedKeys(opts)
+	if opts.Schema == "datadog" {
+		encoderConfig.LevelKey = "status"
+		encoderConfig.EncodeLevel = datadogLevelEncoder
+	}
+	leadingKeys := func(keys []string) []string { return renameKeys(keys, renamed) }
+
+	if opts.TimeUTC {
+		encoderConfig.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+			zapcore.ISO8601TimeEncoder(t.UTC(), enc)
+		}
+	}
+
+	// Create encoder for the configured format
+	var encoder zapcore.Encoder
+	switch opts.Format {
+	case "cef":
+		encoder = newCEFEncoder(encoderConfig, opts.CEF)
+	case "csv":
+		encoder = newCSVEncoder(encoderConfig, opts.CSVColumns)
+	case "console":
+		encoder = newConsoleEncoder(encoderConfig, opts.Color)
+		if opts.LineEnding == "" {
+			encoder = noLineEndingEncoder{encoder}
+		}
+	default:
+		switch {
+		case opts.MessageFirst && opts.SortFields:
+			encoder = newOrderedEncoder(encoderConfig, leadingKeys(messageFirstSortedOrder), true)
+		case opts.MessageFirst:
+			encoder = newOrderedEncoder(encoderConfig, leadingKeys(messageFirstOrder), false)
+		case opts.SortFields:
+			encoder = newOrderedEncoder(encoderConfig, leadingKeys(reservedFieldOrder), true)
+		case opts.LineEnding == "":
+			encoder = noLineEndingEncoder{zapcore.NewJSONEncoder(encoderConfig)}
+		default:
+			encoder = zapcore.NewJSONEncoder(encoderConfig)
+		}
+	}
 
 	// Create write syncer based on output type
 	var writeSyncer zapcore.WriteSyncer
-	if outputType == "file" {
+	headerWritten := false // Whether a CSV output already has its header
+	switch {
+	case opts.Writer != nil:
+		// Custom output registered by the user
+		writeSyncer = opts.Writer
+		if closer, ok := opts.Writer.(io.Closer); ok {
+			pipeline.closers = append(pipeline.closers, closer.Close)
+		}
+	case opts.OutputType == "file" && opts.Checksum:
+		headerWritten = !isEmptyFile(opts.FilePath) // Appending to a file that already has one
+		file, err := newChecksumFile(opts.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		writeSyncer = file
+		pipeline.removeOnClose(opts)
+		pipeline.closers = append(pipeline.closers, file.Close)
+	case opts.OutputType == "file":
 		// File output with rotation via lumberjack
+		maxBackups := opts.MaxBackups
+		if opts.Tiered != nil {
+			maxBackups = 0 // The tier mover moves backups beyond MaxHot instead of deleting them
+		}
 		lumberjackLogger := &lumberjack.Logger{
-			Filename:   filePath,
-			MaxSize:    maxSizeMB,
+			Filename:   opts.FilePath,
+			MaxSize:    opts.MaxSizeMB,
 			MaxBackups: maxBackups,
-			MaxAge:     maxAgeDays,
+			MaxAge:     opts.MaxAgeDays,
 			Compress:   false, // No compression in v1
 		}
+		pipeline.removeOnClose(opts)
+		pipeline.closers = append(pipeline.closers, lumberjackLogger.Close)
 		writeSyncer = zapcore.AddSync(lumberjackLogger)
-	} else {
+		if csv, ok := encoder.(*csvEncoder); ok {
+			// The header goes at the start of each file instead of on every open
+			file, err := newCSVFile(lumberjackLogger, csv.header())
+			if err != nil {
+				return nil, errors.Join(err, pipeline.Close())
+			}
+			writeSyncer = file
+			headerWritten = true
+		}
+	case opts.OutputType == "channel":
+		writeSyncer = newChannelWriteSyncer(opts.Channel, opts.BlockOnFullChannel, opts.Stats)
+	case opts.OutputType == "stderr":
+		writeSyncer = zapcore.AddSync(os.Stderr)
+	default:
 		// stdout output
 		writeSyncer = zapcore.AddSync(os.Stdout)
 	}
 
+	// The header goes straight to the output, not through the wrappers below
+	// that may drop or batch entries
+	if csv, ok := encoder.(*csvEncoder); ok && !headerWritten {
+		if err := csv.writeHeader(writeSyncer); err != nil {
+			return nil, errors.Join(err, pipeline.Close())
+		}
+	}
+
+	if opts.LinePrefix != "" || opts.LineSuffix != "" {
+		writeSyncer = newFramedWriteSyncer(writeSyncer, opts.LinePrefix, opts.LineSuffix)
+	}
+
+	if opts.BatchWindow > 0 {
+		lines := opts.BatchLines || opts.Format != "" && opts.Format != "json"
+		batches := newBatchWriteSyncer(writeSyncer, opts.BatchWindow, opts.MaxBatchEntries, lines, opts.LineEnding)
+		writeSyncer = batches
+		pipeline.closers = append(pipeline.closers, batches.Close)
+	}
+
+	var adaptive *adaptiveController
+	if opts.AdaptiveSampling != nil {
+		adaptive = newAdaptiveController(*opts.AdaptiveSampling, nil, opts.Stats)
+		writeSyncer = &timedWriteSyncer{WriteSyncer: writeSyncer, ctl: adaptive}
+	}
+
+	if opts.SerializeWrites {
+		writeSyncer = zapcore.Lock(writeSyncer)
+	}
+
+	// Reports on the byte limit go straight to the output
+	unlimitedSyncer := writeSyncer
+	if opts.MaxBytesPerSecond > 0 {
+		writeSyncer = newByteLimitedWriteSyncer(writeSyncer, opts.MaxBytesPerSecond, opts.Stats)
+	}
+
+	// Internal errors go to a separate stream so they never mix with entries
+	errorOutput := opts.ErrorOutput
+	if errorOutput == nil {
+		errorOutput = os.Stderr
+	}
+	errorSyncer := zapcore.Lock(zapcore.AddSync(errorOutput))
+
+	if opts.OutputType == "file" && !opts.Checksum && opts.Tiered != nil && opts.Writer == nil {
+		tiers := newTierMover(opts.FilePath, *opts.Tiered, opts.Now, errorSyncer)
+		pipeline.closers = append(pipeline.closers, tiers.close)
+	}
+
 	// Create core
-	core := zapcore.NewCore(encoder, writeSyncer, level)
+	var core zapcore.Core
+	if opts.Async {
+		async := newAsyncCore(encoder, writeSyncer, errorSyncer, opts.Level, opts.AsyncWorkers, opts.AsyncQueueSize)
+		pipeline.async = async.queue
+		if adaptive != nil {
+			adaptive.queueFill = async.queue.fill
+		}
+		pipeline.closers = append(pipeline.closers, async.queue.close)
+		core = async
+	} else {
+		core = zapcore.NewCore(encoder, writeSyncer, opts.Level)
+	}
+
+	unlimitedCore := zapcore.NewCore(encoder.Clone(), unlimitedSyncer, opts.Level)
+
+	// Mirror entries to a human-readable console with its own encoder
+	if opts.Console {
+		consoleOutput := opts.ConsoleOutput
+		if consoleOutput == nil {
+			consoleOutput = os.Stderr
+		}
+		consoleConfig := encoderConfig
+		consoleConfig.LineEnding = zapcore.DefaultLineEnding
+		consoleCore := zapcore.NewCore(
+			newConsoleEncoder(consoleConfig, opts.ConsoleColor),
+			zapcore.Lock(zapcore.AddSync(consoleOutput)),
+			opts.Level,
+		)
+		core = zapcore.NewTee(core, consoleCore)
+		unlimitedCore = zapcore.NewTee(unlimitedCore, consoleCore)
+	}
+
+	for _, sink := range opts.Sinks {
+		sinkCore, err := pipeline.newSinkCore(sink, encoderConfig, opts)
+		if err != nil {
+			return nil, errors.Join(err, pipeline.Close())
+		}
+		core = zapcore.NewTee(core, sinkCore)
+		unlimitedCore = zapcore.NewTee(unlimitedCore, sinkCore)
+	}
+
+	if adaptive != nil {
+		core = &adaptiveCore{Core: core, ctl: adaptive}
+	}
+
+	if opts.SamplingInitial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, opts.SamplingInitial, opts.SamplingThereafter,
+			zapcore.SamplerHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+				if dec&zapcore.LogDropped != 0 {
+					opts.Stats.SamplingDroppedEntries.Add(1)
+				}
+			}),
+		)
+	}
 
 	// Build logger
-	logger := zap.New(core)
+	zapOpts := []zap.Option{zap.ErrorOutput(errorSyncer)}
+	if opts.Now != nil {
+		zapOpts = append(zapOpts, zap.WithClock(clock(opts.Now)))
+	}
+	defaultFields := defaultFields(opts)
+	pipeline.Fields = defaultFields
+	pipeline.Logger = zap.New(core, zapOpts...).With(defaultFields...)
+	pipeline.Unlimited = zap.New(unlimitedCore, zapOpts...).With(defaultFields...)
+
+	return pipeline, nil
+}
+
+// NewCorePipeline creates a Pipeline that writes to core, which takes the
+// place of everything BuildLogger would build from the output, format,
+// sampling, and async options. Entries are still filtered by opts.Level and
+// get the default fields. Pipeline.Unlimited writes to core too.
+func NewCorePipeline(opts Options, core zapcore.Core) *Pipeline {
+	core = &levelFilterCore{Core: core, level: opts.Level}
+	errorOutput := opts.ErrorOutput
+	if errorOutput == nil {
+		errorOutput = os.Stderr
+	}
+	zapOpts := []zap.Option{zap.ErrorOutput(zapcore.Lock(zapcore.AddSync(errorOutput)))}
+	if opts.Now != nil {
+		zapOpts = append(zapOpts, zap.WithClock(clock(opts.Now)))
+	}
+	fields := defaultFields(opts)
+	logger := zap.New(core, zapOpts...).With(fields...)
+	return &Pipeline{Logger: logger, Unlimited: logger, Fields: fields}
+}
+
+// renamedKeys maps the default keys the schema and opts rename to their new
+// names.
+func renamedKeys(opts Options) map[string]string {
+	renamed := map[string]string{}
+	if opts.Schema == "datadog" {
+		maps.Copy(renamed, datadogKeys)
+	}
+	if opts.ServiceKey != "" {
+		renamed["service"] = opts.ServiceKey
+	}
+	if opts.EnvKey != "" {
+		renamed["env"] = opts.EnvKey
+	}
+	return renamed
+}
 
-	// Add service and env as default fields
-	logger = logger.With(
-		zap.String("service", service),
-		zap.String("env", env),
-	)
+// defaultFields returns the fields added to every entry: service, env,
+// version, schema_version, and opts.Fields.
+func defaultFields(opts Options) []zap.Field {
+	renamed := renamedKeys(opts)
+	fields := []zap.Field{
+		zap.String(cmp.Or(renamed["service"], "service"), opts.Service),
+		zap.String(cmp.Or(renamed["env"], "env"), opts.Env),
+	}
+	if opts.Version != "" {
+		fields = append(fields, zap.String(cmp.Or(opts.VersionKey, "version"), opts.Version))
+	}
+	if opts.SchemaVersion != "" {
+		fields = append(fields, zap.String("schema_version", opts.SchemaVersion))
+	}
+	return append(fields, opts.Fields...)
+}
+
+// levelFilterCore passes entries to Core only if level enables them, in
+// addition to Core's own level.
+type levelFilterCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+func (c *levelFilterCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level) && c.Core.Enabled(level)
+}
+
+func (c *levelFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelFilterCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelFilterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// clock adapts a time source to zapcore.Clock.
+type clock func() time.Time
+
+func (c clock) Now() time.Time {
+	return c()
+}
 
-	return logger, nil
+func (c clock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
 }
@@ -0,0 +1,57 @@
+package zapimpl
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// byteLimitedWriteSyncer enforces a byte-rate budget with a token bucket that
+// holds up to one second of budget and refills continuously. An entry larger
+// than the tokens available is dropped and counted rather than delayed, so a
+// flood never blocks callers.
+type byteLimitedWriteSyncer struct {
+	zapcore.WriteSyncer
+	rate  float64 // Bytes per second
+	stats *Stats
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newByteLimitedWriteSyncer(ws zapcore.WriteSyncer, bytesPerSecond int, stats *Stats) *byteLimitedWriteSyncer {
+	return &byteLimitedWriteSyncer{
+		WriteSyncer: ws,
+		rate:        float64(bytesPerSecond),
+		stats:       stats,
+		tokens:      float64(bytesPerSecond),
+		last:        time.Now(),
+	}
+}
+
+func (w *byteLimitedWriteSyncer) Write(p []byte) (int, error) {
+	if !w.take(len(p)) {
+		w.stats.RateLimitedEntries.Add(1)
+		w.stats.RateLimitedBytes.Add(uint64(len(p)))
+		return len(p), nil
+	}
+	return w.WriteSyncer.Write(p)
+}
+
+// take refills the bucket and removes n tokens if there are enough.
+func (w *byteLimitedWriteSyncer) take(n int) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	w.tokens = min(w.tokens+now.Sub(w.last).Seconds()*w.rate, w.rate)
+	w.last = now
+
+	if float64(n) > w.tokens {
+		return false
+	}
+	w.tokens -= float64(n)
+	return true
+}
@@ -0,0 +1,55 @@
+package zapimpl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"go.uber.org/zap/buffer"
+)
+
+// pool provides output buffers for encoders that rewrite the JSON encoding.
+var pool = buffer.NewPool()
+
+// jsonField is one top-level key of an encoded JSON entry.
+type jsonField struct {
+	key   string
+	value json.RawMessage
+}
+
+// decodeJSONEntry splits an encoded JSON entry into its top-level fields,
+// preserving their order.
+func decodeJSONEntry(entry []byte) ([]jsonField, error) {
+	dec := json.NewDecoder(bytes.NewReader(entry))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("encoded entry is not a JSON object")
+	}
+
+	var fields []jsonField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, jsonField{key: key, value: value})
+	}
+	return fields, nil
+}
+
+// text returns the field value as plain text: strings are unquoted, other
+// values keep their JSON representation.
+func (f jsonField) text() string {
+	if len(f.value) > 0 && f.value[0] == '"' {
+		var s string
+		if err := json.Unmarshal(f.value, &s); err == nil {
+			return s
+		}
+	}
+	return string(f.value)
+}
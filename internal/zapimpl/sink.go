@@ -0,0 +1,99 @@
+package zapimpl
+
+import (
+	"io"
+	"os"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// SinkOptions configures an additional output with its own encoding, teed
+// with the pipeline's main output.
+type SinkOptions struct {
+	// Writer, when set, is used as the output instead of OutputType. If it
+	// implements io.Closer it is closed with the pipeline.
+	Writer zapcore.WriteSyncer
+
+	// OutputType is "stdout", "stderr", or "file"; files are rotated with
+	// the pipeline's MaxSizeMB, MaxBackups, and MaxAgeDays.
+	OutputType string
+	FilePath   string
+
+	// Format is "json" (default), "cef", or "csv", using the pipeline's CEF
+	// and CSV settings. Console selects the human-readable console encoding
+	// instead, with colored levels if Color is set.
+	Format  string
+	Console bool
+	Color   bool
+
+	// TimeKey renames the timestamp key; empty keeps "timestamp".
+	// UppercaseLevels writes levels as "INFO" instead of "info".
+	TimeKey         string
+	UppercaseLevels bool
+}
+
+// newSinkCore creates the core of an additional output. base is the
+// encoder config of the main output.
+func (p *Pipeline) newSinkCore(sink SinkOptions, base zapcore.EncoderConfig, opts Options) (zapcore.Core, error) {
+	cfg := base
+	if sink.TimeKey != "" {
+		cfg.TimeKey = sink.TimeKey
+	}
+	if sink.UppercaseLevels {
+		cfg.EncodeLevel = zapcore.CapitalLevelEncoder
+	}
+
+	var encoder zapcore.Encoder
+	switch {
+	case sink.Console:
+		cfg.LineEnding = zapcore.DefaultLineEnding
+		encoder = newConsoleEncoder(cfg, sink.Color)
+	case sink.Format == "cef":
+		encoder = newCEFEncoder(cfg, opts.CEF)
+	case sink.Format == "csv":
+		encoder = newCSVEncoder(cfg, opts.CSVColumns)
+	case cfg.LineEnding == "":
+		encoder = noLineEndingEncoder{zapcore.NewJSONEncoder(cfg)}
+	default:
+		encoder = zapcore.NewJSONEncoder(cfg)
+	}
+
+	var writeSyncer zapcore.WriteSyncer
+	headerWritten := false
+	switch {
+	case sink.Writer != nil:
+		writeSyncer = sink.Writer
+		if closer, ok := sink.Writer.(io.Closer); ok {
+			p.closers = append(p.closers, closer.Close)
+		}
+	case sink.OutputType == "file":
+		lumberjackLogger := &lumberjack.Logger{
+			Filename:   sink.FilePath,
+			MaxSize:    opts.MaxSizeMB,
+			MaxBackups: opts.MaxBackups,
+			MaxAge:     opts.MaxAgeDays,
+		}
+		p.closers = append(p.closers, lumberjackLogger.Close)
+		writeSyncer = zapcore.AddSync(lumberjackLogger)
+		if csv, ok := encoder.(*csvEncoder); ok {
+			file, err := newCSVFile(lumberjackLogger, csv.header())
+			if err != nil {
+				return nil, err
+			}
+			writeSyncer = file
+			headerWritten = true
+		}
+	case sink.OutputType == "stderr":
+		writeSyncer = zapcore.AddSync(os.Stderr)
+	default:
+		writeSyncer = zapcore.AddSync(os.Stdout)
+	}
+
+	if csv, ok := encoder.(*csvEncoder); ok && !headerWritten {
+		if err := csv.writeHeader(writeSyncer); err != nil {
+			return nil, err
+		}
+	}
+	return zapcore.NewCore(encoder, zapcore.Lock(writeSyncer), opts.Level), nil
+}
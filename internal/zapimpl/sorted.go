@@ -0,0 +1,97 @@
+package zapimpl
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// reservedFieldOrder lists the fields that lead every sorted entry, in order.
+var reservedFieldOrder = []string{"timestamp", "level", "message", "service", "env", "trace_id", "metadata"}
+
+// messageFirstOrder lists the fields that lead every message-first entry, in
+// order, without and with SortFields.
+var (
+	messageFirstOrder       = []string{"message", "level", "timestamp"}
+	messageFirstSortedOrder = []string{"message", "level", "timestamp", "service", "env", "trace_id", "metadata"}
+)
+
+// renameKeys returns keys with each key in renamed replaced by its new name.
+func renameKeys(keys []string, renamed map[string]string) []string {
+	out := make([]string, len(keys))
+	for i, key := range keys {
+		out[i] = cmp.Or(renamed[key], key)
+	}
+	return out
+}
+
+// orderedEncoder encodes entries as JSON with the leading fields first, in
+// order, followed by the rest either alphabetically or, if sortRest is false,
+// in the order zap wrote them. Fields with the same key keep their relative order.
+type orderedEncoder struct {
+	zapcore.Encoder
+	leading    []string
+	sortRest   bool
+	lineEnding string
+}
+
+func newOrderedEncoder(cfg zapcore.EncoderConfig, leading []string, sortRest bool) zapcore.Encoder {
+	lineEnding := cfg.LineEnding
+	cfg.LineEnding = "\n"
+	return &orderedEncoder{Encoder: zapcore.NewJSONEncoder(cfg), leading: leading, sortRest: sortRest, lineEnding: lineEnding}
+}
+
+func (e *orderedEncoder) Clone() zapcore.Encoder {
+	return &orderedEncoder{Encoder: e.Encoder.Clone(), leading: e.leading, sortRest: e.sortRest, lineEnding: e.lineEnding}
+}
+
+func (e *orderedEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	encoded, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer encoded.Free()
+
+	jsonFields, err := decodeJSONEntry(encoded.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(jsonFields, func(a, b jsonField) int {
+		if ra, rb := e.rank(a.key), e.rank(b.key); ra != rb {
+			return ra - rb
+		}
+		if e.sortRest {
+			return strings.Compare(a.key, b.key)
+		}
+		return 0
+	})
+
+	buf := pool.Get()
+	buf.AppendByte('{')
+	for i, f := range jsonFields {
+		if i > 0 {
+			buf.AppendByte(',')
+		}
+		key, _ := json.Marshal(f.key)
+		buf.AppendString(string(key))
+		buf.AppendByte(':')
+		buf.AppendString(string(f.value))
+	}
+	buf.AppendByte('}')
+	buf.AppendString(e.lineEnding)
+
+	return buf, nil
+}
+
+// rank returns the position of key in the leading fields, or len(leading)
+// for all other keys.
+func (e *orderedEncoder) rank(key string) int {
+	if i := slices.Index(e.leading, key); i >= 0 {
+		return i
+	}
+	return len(e.leading)
+}
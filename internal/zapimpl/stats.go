@@ -0,0 +1,27 @@
+package zapimpl
+
+import "sync/atomic"
+
+// Stats holds counters shared between a logger, its children, and its outputs.
+type Stats struct {
+	// DroppedEntries counts entries discarded because the output channel was full.
+	DroppedEntries atomic.Uint64
+
+	// AdaptiveDroppedEntries counts entries dropped by the adaptive sampler.
+	AdaptiveDroppedEntries atomic.Uint64
+
+	// RateLimitedEntries and RateLimitedBytes count entries dropped by the
+	// byte-rate limit.
+	RateLimitedEntries atomic.Uint64
+	RateLimitedBytes   atomic.Uint64
+
+	// SamplingDroppedEntries counts entries dropped by the per-second sampler.
+	SamplingDroppedEntries atomic.Uint64
+
+	// DisallowedFields counts fields and metadata keys dropped by the field
+	// key allowlist.
+	DisallowedFields atomic.Uint64
+
+	// SuppressedRepeats counts entries suppressed by the repeat cooldown.
+	SuppressedRepeats atomic.Uint64
+}
@@ -0,0 +1,205 @@
+package zapimpl
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// backupTimeFormat is the timestamp lumberjack puts in the names of rotated
+// files, in UTC: app.log is rotated to app-2025-01-15T10-00-00.000.log.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// tierInterval is how often rotated files are checked for the cold tier.
+const tierInterval = time.Minute
+
+// TieredFiles configures the cold tier of a rotating file output; see
+// tierMover.
+type TieredFiles struct {
+	ColdDir string
+	HotAge  time.Duration
+	MaxHot  int           // Newest rotated files kept hot; older ones move early. 0 keeps all
+	MaxAge  time.Duration // Cold files older than this are deleted; 0 keeps them
+}
+
+// tierMover moves rotated files that are older than HotAge, or beyond the
+// newest MaxHot, from the log file's directory to ColdDir, compressing them
+// with gzip, and deletes cold files older than MaxAge. lumberjack must not
+// prune backups itself, or it would delete files before they move. The
+// active file and recently rotated files stay uncompressed (hot) for fast
+// grepping. Files are checked when the mover starts, every tierInterval, and
+// when it is closed.
+type tierMover struct {
+	cfg         TieredFiles
+	dir         string // Directory of the active file
+	prefix, ext string // Name parts of rotated files around the timestamp
+	now         func() time.Time
+	errorOutput zapcore.WriteSyncer
+
+	mu     sync.Mutex // Serializes sweeps
+	done   chan struct{}
+	exited chan struct{}
+	once   sync.Once
+}
+
+func newTierMover(filePath string, cfg TieredFiles, now func() time.Time, errorOutput zapcore.WriteSyncer) *tierMover {
+	if now == nil {
+		now = time.Now
+	}
+	name := filepath.Base(filePath)
+	ext := filepath.Ext(name)
+	m := &tierMover{
+		cfg:         cfg,
+		dir:         filepath.Dir(filePath),
+		prefix:      strings.TrimSuffix(name, ext) + "-",
+		ext:         ext,
+		now:         now,
+		errorOutput: errorOutput,
+		done:        make(chan struct{}),
+		exited:      make(chan struct{}),
+	}
+	m.sweep()
+	go m.run()
+	return m
+}
+
+func (m *tierMover) run() {
+	defer close(m.exited)
+
+	ticker := time.NewTicker(tierInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			m.sweep()
+		}
+	}
+}
+
+// close stops the mover after a final sweep. It is safe to call more than once.
+func (m *tierMover) close() error {
+	m.once.Do(func() {
+		close(m.done)
+		<-m.exited
+		m.sweep()
+	})
+	return nil
+}
+
+// sweep moves hot files that have aged out and deletes expired cold files,
+// reporting failures to the error output.
+func (m *tierMover) sweep() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	hot, err := os.ReadDir(m.dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		m.reportError(err)
+	}
+	type rotatedFile struct {
+		name    string
+		rotated time.Time
+	}
+	var rotated []rotatedFile
+	for _, entry := range hot {
+		if at, ok := m.rotatedAt(entry, ""); ok {
+			rotated = append(rotated, rotatedFile{entry.Name(), at})
+		}
+	}
+	slices.SortFunc(rotated, func(a, b rotatedFile) int { return b.rotated.Compare(a.rotated) })
+	for i, file := range rotated {
+		if now.Sub(file.rotated) >= m.cfg.HotAge || (m.cfg.MaxHot > 0 && i >= m.cfg.MaxHot) {
+			if err := m.moveCold(file.name); err != nil {
+				m.reportError(err)
+			}
+		}
+	}
+
+	if m.cfg.MaxAge <= 0 {
+		return
+	}
+	cold, err := os.ReadDir(m.cfg.ColdDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		m.reportError(err)
+	}
+	for _, entry := range cold {
+		if rotated, ok := m.rotatedAt(entry, ".gz"); ok && now.Sub(rotated) > m.cfg.MaxAge {
+			if err := os.Remove(filepath.Join(m.cfg.ColdDir, entry.Name())); err != nil {
+				m.reportError(err)
+			}
+		}
+	}
+}
+
+// rotatedAt returns when the file was rotated if entry is a rotated file,
+// followed by suffix.
+func (m *tierMover) rotatedAt(entry os.DirEntry, suffix string) (time.Time, bool) {
+	if !entry.Type().IsRegular() {
+		return time.Time{}, false
+	}
+	ts, ok := strings.CutPrefix(entry.Name(), m.prefix)
+	if !ok {
+		return time.Time{}, false
+	}
+	ts, ok = strings.CutSuffix(ts, m.ext+suffix)
+	if !ok {
+		return time.Time{}, false
+	}
+	rotated, err := time.Parse(backupTimeFormat, ts)
+	return rotated, err == nil
+}
+
+// moveCold compresses the hot file name into ColdDir as name.gz and removes
+// it. The compressed file only appears under its final name once complete.
+func (m *tierMover) moveCold(name string) (err error) {
+	if err := os.MkdirAll(m.cfg.ColdDir, 0o755); err != nil {
+		return err
+	}
+	src, err := os.Open(filepath.Join(m.dir, name))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(m.cfg.ColdDir, name+".gz.tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	gz := gzip.NewWriter(tmp)
+	if _, err := io.Copy(gz, src); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(m.cfg.ColdDir, name+".gz")); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(m.dir, name))
+}
+
+func (m *tierMover) reportError(err error) {
+	_, _ = fmt.Fprintf(m.errorOutput, "%v tiered file error: %v\n", m.now(), err)
+}
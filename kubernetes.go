@@ -0,0 +1,33 @@
+package log
+
+import "os"
+
+// KubernetesFields returns fields describing the pod the process runs in.
+// It reads the environment variables conventionally populated by the
+// Kubernetes downward API and returns a field only for each variable that is set:
+//
+//	POD_NAMESPACE -> k8s_namespace
+//	POD_NAME      -> k8s_pod
+//	NODE_NAME     -> k8s_node
+//
+// Bind the result once at startup:
+//
+//	logger = logger.With(log.KubernetesFields()...)
+func KubernetesFields() []Field {
+	vars := []struct {
+		env string
+		key string
+	}{
+		{"POD_NAMESPACE", "k8s_namespace"},
+		{"POD_NAME", "k8s_pod"},
+		{"NODE_NAME", "k8s_node"},
+	}
+
+	var fields []Field
+	for _, v := range vars {
+		if value := os.Getenv(v.env); value != "" {
+			fields = append(fields, String(v.key, value))
+		}
+	}
+	return fields
+}
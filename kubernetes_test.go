@@ -0,0 +1,32 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestKubernetesFields(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "payments")
+	t.Setenv("POD_NAME", "api-7d9f")
+	t.Setenv("NODE_NAME", "")
+
+	fields := log.KubernetesFields()
+	if len(fields) != 2 {
+		t.Fatalf("expected 2 fields, got %d", len(fields))
+	}
+
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.With(fields...).Info("req-123", "pod metadata", nil)
+
+	logEntry := entries()[0]
+	if logEntry["k8s_namespace"] != "payments" {
+		t.Errorf("expected k8s_namespace=payments, got %v", logEntry["k8s_namespace"])
+	}
+	if logEntry["k8s_pod"] != "api-7d9f" {
+		t.Errorf("expected k8s_pod=api-7d9f, got %v", logEntry["k8s_pod"])
+	}
+	if _, exists := logEntry["k8s_node"]; exists {
+		t.Error("k8s_node should not be present when NODE_NAME is empty")
+	}
+}
@@ -27,11 +27,18 @@ const (
 	// Applications running smoothly should not generate error-level logs.
 	ErrorLevel Level = "error"
 
+	// PanicLevel is for errors the application cannot continue from but
+	// that callers may recover from. After logging, Panic panics.
+	PanicLevel Level = "panic"
+
 	// FatalLevel is for critical errors that cause the application to exit.
-	// After logging, the application will call os.Exit(1).
+	// After logging, the application will call os.Exit (see Config.FatalExitCode).
 	FatalLevel Level = "fatal"
 )
 
+// levels lists the levels from most to least verbose.
+var levels = []Level{DebugLevel, InfoLevel, WarnLevel, ErrorLevel, PanicLevel, FatalLevel}
+
 // toZapLevel converts a Level to zapcore.Level.
 func (l Level) toZapLevel() (zapcore.Level, error) {
 	switch strings.ToLower(string(l)) {
@@ -43,14 +50,67 @@ func (l Level) toZapLevel() (zapcore.Level, error) {
 		return zapcore.WarnLevel, nil
 	case "error":
 		return zapcore.ErrorLevel, nil
+	case "panic":
+		return zapcore.PanicLevel, nil
 	case "fatal":
 		return zapcore.FatalLevel, nil
 	default:
-		return zapcore.InfoLevel, fmt.Errorf("invalid log level: %s (valid: debug, info, warn, error, fatal)", l)
+		return zapcore.InfoLevel, fmt.Errorf("invalid log level: %s (valid: debug, info, warn, error, panic, fatal)", l)
+	}
+}
+
+// levelFromZap converts a zapcore.Level to a Level. Levels this package
+// doesn't have map to the one that enables the same entries: dpanic to panic,
+// since nothing is logged at dpanic, and levels beyond the ends, such as the
+// one NewNop disables everything with, to debug or fatal.
+func levelFromZap(level zapcore.Level) Level {
+	switch level {
+	case zapcore.DebugLevel:
+		return DebugLevel
+	case zapcore.InfoLevel:
+		return InfoLevel
+	case zapcore.WarnLevel:
+		return WarnLevel
+	case zapcore.ErrorLevel:
+		return ErrorLevel
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return PanicLevel
+	case zapcore.FatalLevel:
+		return FatalLevel
+	}
+	if level < zapcore.DebugLevel {
+		return DebugLevel
 	}
+	return FatalLevel
 }
 
 // String returns the string representation of the Level.
 func (l Level) String() string {
 	return string(l)
 }
+
+// SetLevel changes the minimum level of entries the logger writes, without a
+// restart. The level is shared with the logger's parent and children, so the
+// change applies to all of them. Levels are parsed like Config.Level.
+//
+// Example:
+//
+//	if err := logger.SetLevel(log.DebugLevel); err != nil {
+//	    return err
+//	}
+//
+// Returns an error, leaving the level unchanged, if level is not valid.
+func (l *Logger) SetLevel(level Level) error {
+	zapLevel, err := level.toZapLevel()
+	if err != nil {
+		return err
+	}
+	l.level.SetLevel(zapLevel)
+	return nil
+}
+
+// GetLevel returns the minimum level of entries the logger writes, which
+// SetLevel and EnableSignalLevelControl change.
+func (l *Logger) GetLevel() Level {
+	return levelFromZap(l.level.Level())
+}
@@ -0,0 +1,72 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// levelHandlerTraceID is the traceId of entries reporting level changes made
+// through LevelHandler.
+const levelHandlerTraceID = "log-level-http"
+
+// levelPayload is the JSON body LevelHandler reads and writes.
+type levelPayload struct {
+	Level Level `json:"level"`
+}
+
+// LevelHandler returns an HTTP handler for reading and changing the logger's
+// level at runtime, like zap's AtomicLevel.ServeHTTP but accepting only this
+// package's levels. The level is shared with the logger's parent and
+// children (see SetLevel).
+//
+//   - GET responds with the current level: {"level":"info"}
+//   - PUT with a body such as {"level":"debug"} changes the level and
+//     responds with the new one. Invalid JSON or an unknown level gets
+//     400 Bad Request with {"error":"..."}, leaving the level unchanged.
+//
+// Other methods get 405 Method Not Allowed. Each change is logged with
+// 'from' and 'to' fields. The handler does no authentication; mount it
+// behind your own.
+//
+// Example:
+//
+//	mux.Handle("/admin/loglevel", requireAdmin(logger.LevelHandler()))
+//
+//	// curl -X PUT -d '{"level":"debug"}' localhost:8080/admin/loglevel
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, levelPayload{Level: l.GetLevel()})
+		case http.MethodPut:
+			var payload levelPayload
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid body: %v", err)})
+				return
+			}
+			from := l.level.Level()
+			if err := l.SetLevel(payload.Level); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			to := l.level.Level()
+			l.log(levelChangeLevel(to), 1, time.Time{}, levelHandlerTraceID, "log level changed", nil, []Field{
+				String("from", from.String()),
+				String("to", to.String()),
+			})
+			writeJSON(w, http.StatusOK, levelPayload{Level: l.GetLevel()})
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method must be GET or PUT"})
+		}
+	})
+}
+
+// writeJSON writes v as the JSON response body with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v) // The client may be gone; there is nothing to report it to
+}
@@ -0,0 +1,50 @@
+package log_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_LevelHandler(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{Level: log.InfoLevel})
+	handler := logger.LevelHandler()
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/admin/loglevel", strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := serve(http.MethodGet, ""); rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"level":"info"}` {
+		t.Errorf("expected GET to return the level, got %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := serve(http.MethodPut, `{"level":"debug"}`); rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"level":"debug"}` {
+		t.Errorf("expected PUT to return the new level, got %d %s", rec.Code, rec.Body)
+	}
+	if got := logger.GetLevel(); got != log.DebugLevel {
+		t.Errorf("expected level debug after PUT, got %s", got)
+	}
+	if got := entries(); len(got) != 1 || got[0]["message"] != "log level changed" || got[0]["from"] != "info" || got[0]["to"] != "debug" {
+		t.Errorf("expected the change to be logged, got %v", got)
+	}
+
+	for _, body := range []string{`{"level":"verbose"}`, `not json`} {
+		rec := serve(http.MethodPut, body)
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"error"`) {
+			t.Errorf("expected 400 for %s, got %d %s", body, rec.Code, rec.Body)
+		}
+	}
+	if got := logger.GetLevel(); got != log.DebugLevel {
+		t.Errorf("expected invalid PUTs to leave the level unchanged, got %s", got)
+	}
+
+	if rec := serve(http.MethodPost, `{"level":"warn"}`); rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, PUT" {
+		t.Errorf("expected 405 with Allow header, got %d %v", rec.Code, rec.Header())
+	}
+}
@@ -1,18 +1,83 @@
 package log
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"time"
 
 	"github.com/glennprays/log/internal/zapimpl"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // Logger provides structured logging with required traceId and metadata fields.
 // All log methods require a traceId for request traceability and accept optional
 // metadata for contextual information.
 type Logger struct {
-	zapLogger    *zap.Logger
-	enableCaller bool // Cached from config for fast runtime access
+	zapLogger *zap.Logger
+	fields    []zap.Field       // Bound with With, already in zapLogger unless fieldNamespace is set; see Merge
+	pipeline  *zapimpl.Pipeline // Shared with child loggers
+	stats     *zapimpl.Stats    // Shared with child loggers
+	level     zap.AtomicLevel   // Shared with child loggers
+
+	component     string   // Dotted component path, see Component
+	correlationID string   // See WithCorrelation
+	traceID       string   // Default traceId, see WithDefaultTraceID
+	eventType     string   // See Event
+	tags          []string // See WithTags; never appended to in place
+	clock         Clock
+	created       time.Time // Shared with child loggers, for uptime_ms
+
+	// Cached from config for fast runtime access
+	enableCaller          bool
+	callerMinLevel        zapcore.Level
+	callerTrimPrefix      string
+	largeNumbersAsString  bool
+	boolsAsStrings        bool
+	omitEmptyFields       bool
+	includeGoroutineID    bool
+	includeUptime         bool
+	promoteMetadataKeys   []string
+	eventTypes            map[string]bool // nil allows any event type
+	allowedFieldKeys      map[string]bool // nil allows any key
+	metadataSerializer    func(any) (json.RawMessage, error)
+	maxMetadataDepth      int
+	preEmit               func(level Level, msg string, fields *[]Field)
+	stacktraceEnabled     bool
+	stacktraceLevel       zapcore.Level
+	maxStacktraceDepth    int
+	fatalExitCode         int
+	includeSampleDecision bool
+	allowEmptyTraceID     bool
+	datadogSchema         bool                   // Entries use SchemaDatadog; see ContextWithDatadogTrace
+	fieldNamespace        string                 // Bound fields are added by log instead of zapLogger when set
+	repeats               *repeatFilter          // nil without RepeatCooldown; shared with child loggers
+	bootstrap             *zapimpl.BootstrapCore // Set for loggers created by Bootstrap
+}
+
+// exit terminates the process after a fatal entry; replaced in tests.
+var exit = os.Exit
+
+// terminalHook is the zapcore.CheckWriteHook that ends a fatal or panic
+// entry. It first syncs the logger, so the entry and every entry buffered or
+// queued before it are written, then exits with code or, for panic entries,
+// panics with the message.
+type terminalHook struct {
+	logger *Logger
+	code   int
+}
+
+func (h terminalHook) OnWrite(ce *zapcore.CheckedEntry, _ []zapcore.Field) {
+	h.logger.pipeline.MarkFailed()
+	_ = h.logger.Sync() // The process is about to end; there is nowhere to report a failure
+	if ce.Level == zapcore.PanicLevel {
+		panic(ce.Message)
+	}
+	exit(h.code)
 }
 
 // New creates a new Logger instance with the provided configuration.
@@ -27,33 +92,247 @@ type Logger struct {
 //	    Output:  log.OutputStdout,
 //	})
 func New(cfg Config) (*Logger, error) {
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid config: %w", err)
+	return newLogger(cfg, nil, nil)
+}
+
+// NewNop returns a Logger that discards every entry. Like a zap no-op logger,
+// Fatal still exits the process. Use it in tests, or as a default where a
+// Logger is optional.
+func NewNop() *Logger {
+	nop := zap.NewNop()
+	return &Logger{
+		zapLogger:      nop,
+		pipeline:       &zapimpl.Pipeline{Logger: nop},
+		stats:          &zapimpl.Stats{},
+		level:          zap.NewAtomicLevelAt(zapcore.FatalLevel + 1),
+		clock:          systemClock{},
+		created:        time.Now(),
+		callerMinLevel: zapcore.DebugLevel,
+		fatalExitCode:  1,
 	}
+}
 
-	zapLevel, err := cfg.Level.toZapLevel()
+// NewWithCore creates a Logger that writes to core instead of building an
+// output from cfg. It is an escape hatch for advanced uses, such as a
+// specialized sampler or a router that sends each tenant's entries elsewhere,
+// that keeps the Logger API and its enrichment: service, env, and the other
+// default fields are bound to core, and each entry still gets trace_id,
+// metadata, and the fields enabled in cfg. Entries below cfg.Level are not
+// passed to core.
+//
+// The core is responsible for encoding and writing entries, so the output,
+// format, schema, sampling, async, and byte-limit settings of cfg are not
+// used. Output may be left empty. Close does not close core.
+//
+// Example:
+//
+//	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), tenantRouter, zapcore.DebugLevel)
+//	logger, err := log.NewWithCore(log.Config{
+//	    Service: "my-service",
+//	    Env:     "production",
+//	    Level:   log.InfoLevel,
+//	}, core)
+//
+// Panics if core is nil.
+func NewWithCore(cfg Config, core zapcore.Core) (*Logger, error) {
+	if core == nil {
+		panic("log: core cannot be nil")
+	}
+	if cfg.Output == "" {
+		cfg.Output = OutputStdout // Not used
+	}
+	return newLogger(cfg, nil, core)
+}
+
+// newLogger creates a Logger. A non-nil writer replaces the configured output,
+// and a non-nil core replaces the output pipeline altogether.
+func newLogger(cfg Config, writer WriteSyncer, core zapcore.Core) (*Logger, error) {
+	resolved, err := Resolve(cfg)
 	if err != nil {
 		return nil, err
 	}
+	cfg = resolved.Config
+	if resolved.LevelFellBack() {
+		fmt.Fprintf(internalErrorWriter(cfg), "log: invalid log level %q, falling back to %q\n", resolved.RequestedLevel, cfg.Level)
+	}
 
-	zapLogger, err := zapimpl.BuildLogger(
-		cfg.Service,
-		cfg.Env,
-		zapLevel,
-		string(cfg.Output),
-		cfg.FilePath,
-		cfg.MaxSizeMB,
-		cfg.MaxBackups,
-		cfg.MaxAgeDays,
-	)
+	zapLevel, err := cfg.Level.toZapLevel()
 	if err != nil {
+		return nil, err
+	}
+
+	var defaultFields []Field
+	if cfg.IncludeBuildInfo {
+		defaultFields = append(defaultFields, buildInfoFields()...)
+	}
+
+	if cfg.Output == OutputFile && cfg.FallbackToStdout && core == nil {
+		if err := checkFileOutput(cfg.FilePath); err != nil {
+			cfg = fallBackToStdout(cfg, err)
+		}
+	}
+
+	if factory, ok := registeredOutput(cfg.Output); ok && writer == nil && core == nil {
+		writer, err = factory(cfg)
+		if err == nil && writer == nil {
+			err = errors.New("factory returned nil")
+		}
+		if err != nil {
+			if !cfg.FallbackToStdout {
+				return nil, fmt.Errorf("failed to create output %q: %w", cfg.Output, err)
+			}
+			writer = nil
+			cfg = fallBackToStdout(cfg, err)
+		}
+	}
+
+	var sinks []zapimpl.SinkOptions
+	if core == nil {
+		if sinks, err = sinkOptions(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	var samplingInitial, samplingThereafter int
+	if cfg.Sampling != nil {
+		samplingInitial, samplingThereafter = cfg.Sampling.Initial, cfg.Sampling.Thereafter
+	}
+
+	level := zap.NewAtomicLevelAt(zapLevel)
+	var adaptiveSampling *zapimpl.AdaptiveSampling
+	if cfg.AdaptiveSampling != nil {
+		adaptiveSampling = &zapimpl.AdaptiveSampling{
+			LatencyThreshold: cfg.AdaptiveSampling.LatencyThreshold,
+			QueueThreshold:   cfg.AdaptiveSampling.QueueThreshold,
+			Interval:         cfg.AdaptiveSampling.Interval,
+			MaxRatio:         cfg.AdaptiveSampling.MaxRatio,
+		}
+	}
+
+	stats := &zapimpl.Stats{}
+	opts := zapimpl.Options{
+		Service: cfg.Service,
+		Env:     cfg.Env,
+		Level:   level,
+		Format:  string(cfg.Format),
+		Color:   cfg.Format == FormatConsole && writer == nil && IsTerminal(cfg.Output),
+		CEF: zapimpl.CEFOptions{
+			Vendor:  cfg.CEFVendor,
+			Product: cfg.Service,
+			Version: cfg.CEFVersion,
+		},
+		CSVColumns:         cfg.CSVColumns,
+		Schema:             string(cfg.Schema),
+		SortFields:         cfg.SortFields,
+		MessageFirst:       cfg.MessageFirst,
+		TimeUTC:            cfg.TimeUTC,
+		Now:                cfg.Clock.Now,
+		ServiceKey:         cfg.FieldNames.ServiceKey,
+		EnvKey:             cfg.FieldNames.EnvKey,
+		Version:            cfg.Version,
+		VersionKey:         cfg.FieldNames.VersionKey,
+		SchemaVersion:      cfg.SchemaVersion,
+		Fields:             toZapFields(defaultFields),
+		Writer:             writer,
+		OutputType:         string(cfg.Output),
+		FilePath:           cfg.FilePath,
+		Checksum:           cfg.Checksum,
+		EphemeralFile:      cfg.EphemeralFile,
+		MaxSizeMB:          cfg.MaxSizeMB,
+		MaxBackups:         cfg.MaxBackups,
+		MaxAgeDays:         cfg.MaxAgeDays,
+		Tiered:             tieredFiles(cfg),
+		Channel:            cfg.Channel,
+		BlockOnFullChannel: cfg.BlockOnFullChannel,
+		LineEnding:         lineEnding(cfg.LineEnding),
+		LinePrefix:         cfg.LinePrefix,
+		LineSuffix:         cfg.LineSuffix,
+		SerializeWrites:    cfg.SerializeWrites,
+		BatchWindow:        cfg.BatchWindow,
+		MaxBatchEntries:    cfg.MaxBatchEntries,
+		BatchLines:         cfg.BatchLines,
+		MaxBytesPerSecond:  cfg.MaxBytesPerSecond,
+		SamplingInitial:    samplingInitial,
+		SamplingThereafter: samplingThereafter,
+		AdaptiveSampling:   adaptiveSampling,
+		Async:              cfg.Async,
+		AsyncWorkers:       cfg.AsyncWorkers,
+		AsyncQueueSize:     cfg.AsyncQueueSize,
+		Console:            cfg.Console,
+		ConsoleOutput:      cfg.ConsoleWriter,
+		ConsoleColor:       cfg.Console && IsTerminalWriter(consoleWriter(cfg)),
+		Sinks:              sinks,
+		ErrorOutput:        cfg.InternalErrorWriter,
+		Stats:              stats,
+	}
+	var pipeline *zapimpl.Pipeline
+	if core != nil {
+		pipeline = zapimpl.NewCorePipeline(opts, core)
+	} else if pipeline, err = zapimpl.BuildLogger(opts); err != nil {
 		return nil, fmt.Errorf("failed to build logger: %w", err)
 	}
 
-	return &Logger{
-		zapLogger:    zapLogger,
-		enableCaller: cfg.EnableCaller,
-	}, nil
+	logger := &Logger{
+		zapLogger:            pipeline.Logger,
+		pipeline:             pipeline,
+		stats:                stats,
+		level:                level,
+		clock:                cfg.Clock,
+		created:              cfg.Clock.Now(),
+		enableCaller:         cfg.EnableCaller,
+		callerTrimPrefix:     cfg.CallerTrimPrefix,
+		largeNumbersAsString: cfg.LargeNumbersAsString,
+		boolsAsStrings:       cfg.BoolsAsStrings,
+		omitEmptyFields:      cfg.OmitEmptyFields,
+		includeGoroutineID:   cfg.IncludeGoroutineID,
+		includeUptime:        cfg.IncludeUptime,
+		promoteMetadataKeys:  cfg.PromoteMetadataKeys,
+		metadataSerializer:   cfg.MetadataSerializer,
+		maxMetadataDepth:     cfg.MaxMetadataDepth,
+		preEmit:              cfg.PreEmit,
+		maxStacktraceDepth:   cfg.MaxStacktraceDepth,
+		fatalExitCode:        cfg.FatalExitCode,
+		fieldNamespace:       cfg.FieldNamespace,
+		allowEmptyTraceID:    cfg.AllowEmptyTraceID,
+		datadogSchema:        cfg.Schema == SchemaDatadog,
+	}
+	if cfg.RepeatCooldown > 0 {
+		logger.repeats = newRepeatFilter(cfg.RepeatCooldown)
+	}
+	if cfg.AllowedFieldKeys != nil {
+		logger.allowedFieldKeys = make(map[string]bool, len(cfg.AllowedFieldKeys))
+		for _, key := range cfg.AllowedFieldKeys {
+			logger.allowedFieldKeys[key] = true
+		}
+		logger.promoteMetadataKeys = slices.DeleteFunc(slices.Clone(cfg.PromoteMetadataKeys), func(key string) bool {
+			return !logger.allowedFieldKeys[key]
+		})
+	}
+	if cfg.EventTypes != nil {
+		logger.eventTypes = make(map[string]bool, len(cfg.EventTypes))
+		for _, eventType := range cfg.EventTypes {
+			logger.eventTypes[eventType] = true
+		}
+	}
+	if cfg.CallerMinLevel != "" {
+		logger.callerMinLevel, _ = cfg.CallerMinLevel.toZapLevel()
+	} else {
+		logger.callerMinLevel = zapcore.DebugLevel
+	}
+	if cfg.StacktraceLevel != "" {
+		logger.stacktraceEnabled = true
+		logger.stacktraceLevel, _ = cfg.StacktraceLevel.toZapLevel()
+	}
+
+	if cfg.MaxBytesPerSecond > 0 {
+		logger.startRateLimitSummaries(cfg.MaxBytesPerSecond)
+	}
+	if cfg.IncludeSampleDecision && (cfg.Sampling != nil || cfg.AdaptiveSampling != nil) && core == nil {
+		logger.includeSampleDecision = true
+		logger.startSamplingSummaries()
+	}
+
+	return logger, nil
 }
 
 // With creates a child logger with pre-bound fields.
@@ -75,11 +354,83 @@ func (l *Logger) With(fields ...Field) *Logger {
 	if len(fields) == 0 {
 		return l
 	}
-	zapFields := toZapFields(fields)
-	return &Logger{
-		zapLogger:    l.zapLogger.With(zapFields...),
-		enableCaller: l.enableCaller, // Preserve parent's setting
+	prepared := l.prepareFields(fields)
+	child := *l // Preserve parent's settings
+	child.zapLogger = l.bindFields(l.zapLogger, prepared)
+	child.fields = append(slices.Clip(l.fields), prepared...)
+	return &child
+}
+
+// Merge creates a child logger carrying the bound fields of both l and other.
+// When both loggers bound a field with the same key, other's value wins, so
+// merging a request logger into a base logger lets request fields override
+// global ones. Everything else, including the output, level, and component,
+// comes from l; other is only a source of fields.
+//
+// Example:
+//
+//	base := logger.With(log.String("region", "eu-west-1"))
+//	requestLogger := logger.With(log.String("user_id", "user-456"))
+//	base.Merge(requestLogger).Info("req-123", "checkout", nil) // includes region and user_id
+func (l *Logger) Merge(other *Logger) *Logger {
+	if other == nil || len(other.fields) == 0 {
+		return l
 	}
+
+	overridden := make(map[string]bool, len(other.fields))
+	for _, field := range other.fields {
+		overridden[field.Key] = true
+	}
+	merged := make([]zap.Field, 0, len(l.fields)+len(other.fields))
+	for _, field := range l.fields {
+		if !overridden[field.Key] {
+			merged = append(merged, field)
+		}
+	}
+	merged = append(merged, other.fields...)
+
+	child := *l
+	child.zapLogger = l.bindFields(l.pipeline.Logger, merged)
+	child.fields = merged
+	return &child
+}
+
+// Tee returns a child logger that writes every entry to extra as well as to
+// the logger's own output, for example to assert on entries in an
+// integration test while the real output still writes. extra receives the
+// entries the logger's level enables, with the same fields, including those
+// bound before and after Tee; the output's sampling and limits don't apply to
+// it. The parent logger is unchanged.
+//
+// Example:
+//
+//	core, observed := observer.New(zapcore.DebugLevel) // go.uber.org/zap/zaptest/observer
+//	logger = logger.Tee(core)
+//	// ... exercise the system
+//	if observed.FilterMessage("order placed").Len() != 1 {
+//	    t.Error("expected an order placed entry")
+//	}
+//
+// Panics if extra is nil.
+func (l *Logger) Tee(extra zapcore.Core) *Logger {
+	if extra == nil {
+		panic("log: tee core cannot be nil")
+	}
+
+	child := *l
+	child.pipeline = l.pipeline.Tee(extra, l.level)
+	child.zapLogger = l.bindFields(child.pipeline.Logger, l.fields)
+	return &child
+}
+
+// bindFields returns base with fields bound. With Config.FieldNamespace the
+// fields must follow the reserved fields of each entry, so base is returned
+// as is and log adds l.fields to every entry instead.
+func (l *Logger) bindFields(base *zap.Logger, fields []zap.Field) *zap.Logger {
+	if l.fieldNamespace != "" {
+		return base
+	}
+	return base.With(fields...)
 }
 
 // Debug logs a message at debug level.
@@ -90,27 +441,9 @@ func (l *Logger) With(fields ...Field) *Logger {
 //   - metadata: Contextual information (can be nil, always included in output)
 //   - fields: Additional structured fields (optional)
 //
-// Panics if traceId is empty.
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
 func (l *Logger) Debug(traceId string, msg string, metadata any, fields ...Field) {
-	if traceId == "" {
-		panic("log: traceId cannot be empty")
-	}
-	zapFields := toZapFields(fields)
-	zapFields = append(zapFields,
-		zap.String("trace_id", traceId),
-		zap.Any("metadata", metadata),
-	)
-
-	// Add caller and function only if enabled
-	if l.enableCaller {
-		caller := getCaller(1)
-		zapFields = append(zapFields,
-			zap.String("caller", fmt.Sprintf("%s:%d", caller.file, caller.line)),
-			zap.String("function", caller.function),
-		)
-	}
-
-	l.zapLogger.Debug(msg, zapFields...)
+	l.log(zapcore.DebugLevel, 1, time.Time{}, traceId, msg, metadata, fields)
 }
 
 // Info logs a message at info level.
@@ -121,27 +454,9 @@ func (l *Logger) Debug(traceId string, msg string, metadata any, fields ...Field
 //   - metadata: Contextual information (can be nil, always included in output)
 //   - fields: Additional structured fields (optional)
 //
-// Panics if traceId is empty.
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
 func (l *Logger) Info(traceId string, msg string, metadata any, fields ...Field) {
-	if traceId == "" {
-		panic("log: traceId cannot be empty")
-	}
-	zapFields := toZapFields(fields)
-	zapFields = append(zapFields,
-		zap.String("trace_id", traceId),
-		zap.Any("metadata", metadata),
-	)
-
-	// Add caller and function only if enabled
-	if l.enableCaller {
-		caller := getCaller(1)
-		zapFields = append(zapFields,
-			zap.String("caller", fmt.Sprintf("%s:%d", caller.file, caller.line)),
-			zap.String("function", caller.function),
-		)
-	}
-
-	l.zapLogger.Info(msg, zapFields...)
+	l.log(zapcore.InfoLevel, 1, time.Time{}, traceId, msg, metadata, fields)
 }
 
 // Warn logs a message at warn level.
@@ -152,27 +467,9 @@ func (l *Logger) Info(traceId string, msg string, metadata any, fields ...Field)
 //   - metadata: Contextual information (can be nil, always included in output)
 //   - fields: Additional structured fields (optional)
 //
-// Panics if traceId is empty.
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
 func (l *Logger) Warn(traceId string, msg string, metadata any, fields ...Field) {
-	if traceId == "" {
-		panic("log: traceId cannot be empty")
-	}
-	zapFields := toZapFields(fields)
-	zapFields = append(zapFields,
-		zap.String("trace_id", traceId),
-		zap.Any("metadata", metadata),
-	)
-
-	// Add caller and function only if enabled
-	if l.enableCaller {
-		caller := getCaller(1)
-		zapFields = append(zapFields,
-			zap.String("caller", fmt.Sprintf("%s:%d", caller.file, caller.line)),
-			zap.String("function", caller.function),
-		)
-	}
-
-	l.zapLogger.Warn(msg, zapFields...)
+	l.log(zapcore.WarnLevel, 1, time.Time{}, traceId, msg, metadata, fields)
 }
 
 // Error logs a message at error level.
@@ -183,30 +480,30 @@ func (l *Logger) Warn(traceId string, msg string, metadata any, fields ...Field)
 //   - metadata: Contextual information (can be nil, always included in output)
 //   - fields: Additional structured fields (optional)
 //
-// Panics if traceId is empty.
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
 func (l *Logger) Error(traceId string, msg string, metadata any, fields ...Field) {
-	if traceId == "" {
-		panic("log: traceId cannot be empty")
-	}
-	zapFields := toZapFields(fields)
-	zapFields = append(zapFields,
-		zap.String("trace_id", traceId),
-		zap.Any("metadata", metadata),
-	)
-
-	// Add caller and function only if enabled
-	if l.enableCaller {
-		caller := getCaller(1)
-		zapFields = append(zapFields,
-			zap.String("caller", fmt.Sprintf("%s:%d", caller.file, caller.line)),
-			zap.String("function", caller.function),
-		)
-	}
+	l.log(zapcore.ErrorLevel, 1, time.Time{}, traceId, msg, metadata, fields)
+}
 
-	l.zapLogger.Error(msg, zapFields...)
+// Panic logs a message at panic level, then panics with msg. Like Fatal, it
+// flushes the logger first, so buffered entries are not lost if the panic
+// ends the process.
+//
+// Parameters:
+//   - traceId: Trace identifier for request traceability (required, panics if empty)
+//   - msg: Human-readable log message (required)
+//   - metadata: Contextual information (can be nil, always included in output)
+//   - fields: Additional structured fields (optional)
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+// After logging, this method panics.
+func (l *Logger) Panic(traceId string, msg string, metadata any, fields ...Field) {
+	l.log(zapcore.PanicLevel, 1, time.Time{}, traceId, msg, metadata, fields)
 }
 
-// Fatal logs a message at fatal level, then calls os.Exit(1).
+// Fatal logs a message at fatal level, flushes the logger so the entry and
+// all buffered entries are written, then calls os.Exit with
+// Config.FatalExitCode (default 1).
 //
 // Parameters:
 //   - traceId: Trace identifier for request traceability (required, panics if empty)
@@ -214,27 +511,251 @@ func (l *Logger) Error(traceId string, msg string, metadata any, fields ...Field
 //   - metadata: Contextual information (can be nil, always included in output)
 //   - fields: Additional structured fields (optional)
 //
-// Panics if traceId is empty. After logging, this method calls os.Exit(1).
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+// After logging, this method calls os.Exit.
 func (l *Logger) Fatal(traceId string, msg string, metadata any, fields ...Field) {
-	if traceId == "" {
-		panic("log: traceId cannot be empty")
+	l.log(zapcore.FatalLevel, 1, time.Time{}, traceId, msg, metadata, fields)
+}
+
+// FatalCode logs a message at fatal level, then calls os.Exit(code), for
+// signaling distinct fatal conditions to a supervisor.
+//
+// Example:
+//
+//	const exitConfigError = 78 // EX_CONFIG
+//	logger.FatalCode(exitConfigError, "startup", "config not found", nil, log.String("path", path))
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID),
+// or if code is not between 1 and 255.
+func (l *Logger) FatalCode(code int, traceId string, msg string, metadata any, fields ...Field) {
+	if code < 1 || code > 255 {
+		panic(fmt.Sprintf("log: fatal exit code must be between 1 and 255 (got: %d)", code))
 	}
-	zapFields := toZapFields(fields)
-	zapFields = append(zapFields,
-		zap.String("trace_id", traceId),
-		zap.Any("metadata", metadata),
-	)
-
-	// Add caller and function only if enabled
-	if l.enableCaller {
-		caller := getCaller(1)
+	withCode := *l
+	withCode.fatalExitCode = code
+	withCode.log(zapcore.FatalLevel, 1, time.Time{}, traceId, msg, metadata, fields)
+}
+
+// FatalUnlessCancelled is like Fatal, unless ctx is already done: then the
+// failure is most likely a side effect of a graceful shutdown, so it logs at
+// error level with the context's error as 'context_error' and returns
+// instead of exiting.
+//
+// Example:
+//
+//	if err := server.Serve(listener); err != nil {
+//	    logger.FatalUnlessCancelled(ctx, "startup", "server stopped", nil, log.Error(err))
+//	}
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+func (l *Logger) FatalUnlessCancelled(ctx context.Context, traceId string, msg string, metadata any, fields ...Field) {
+	if ctx.Err() == nil {
+		l.log(zapcore.FatalLevel, 1, time.Time{}, traceId, msg, metadata, fields)
+		return
+	}
+	fields = append(slices.Clip(fields), String("context_error", context.Cause(ctx).Error()))
+	l.log(zapcore.ErrorLevel, 1, time.Time{}, traceId, msg, metadata, fields)
+}
+
+// InfoMeta logs a message at info level with a single metadata entry.
+// It is shorthand for Info with metadata map[string]any{key: value}.
+//
+// Example:
+//
+//	logger.InfoMeta("req-123", "cache hit", "cache_key", key)
+//	// "metadata": {"cache_key": "..."}
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+func (l *Logger) InfoMeta(traceId string, msg string, key string, value any, fields ...Field) {
+	l.log(zapcore.InfoLevel, 1, time.Time{}, traceId, msg, map[string]any{key: value}, fields)
+}
+
+// DebugAt logs a message at debug level with the given timestamp instead of
+// the current time. Use it to replay or backfill historical events.
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+func (l *Logger) DebugAt(t time.Time, traceId string, msg string, metadata any, fields ...Field) {
+	l.log(zapcore.DebugLevel, 1, t, traceId, msg, metadata, fields)
+}
+
+// InfoAt logs a message at info level with the given timestamp instead of
+// the current time. Use it to replay or backfill historical events.
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+func (l *Logger) InfoAt(t time.Time, traceId string, msg string, metadata any, fields ...Field) {
+	l.log(zapcore.InfoLevel, 1, t, traceId, msg, metadata, fields)
+}
+
+// WarnAt logs a message at warn level with the given timestamp instead of
+// the current time. Use it to replay or backfill historical events.
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+func (l *Logger) WarnAt(t time.Time, traceId string, msg string, metadata any, fields ...Field) {
+	l.log(zapcore.WarnLevel, 1, t, traceId, msg, metadata, fields)
+}
+
+// ErrorAt logs a message at error level with the given timestamp instead of
+// the current time. Use it to replay or backfill historical events.
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+func (l *Logger) ErrorAt(t time.Time, traceId string, msg string, metadata any, fields ...Field) {
+	l.log(zapcore.ErrorLevel, 1, t, traceId, msg, metadata, fields)
+}
+
+// log writes an entry at the given level. skip is the number of stack frames
+// between log and the user's call site (1 when called from a level method).
+// A non-zero at overrides the entry's timestamp.
+func (l *Logger) log(level zapcore.Level, skip int, at time.Time, traceId string, msg string, metadata any, fields []Field) {
+	traceId = l.resolveTraceID(traceId)
+
+	zapLogger := l.zapLogger
+	mustLog := slices.ContainsFunc(fields, isMustLog) && l.pipeline.Unlimited != nil
+	if mustLog {
+		zapLogger = l.pipeline.Unlimited // Has no bound fields; they are added below
+	}
+
+	ce := zapLogger.Check(level, msg)
+	if ce == nil {
+		return
+	}
+	var repeat repeatReport
+	if l.repeats != nil && level <= zapcore.ErrorLevel && !mustLog {
+		ok, reports := l.repeats.admit(traceId, msg, level, l.clock.Now())
+		if !ok {
+			l.stats.SuppressedRepeats.Add(1)
+			return
+		}
+		if len(reports) > 0 {
+			// The entry reports the most recent message; older ones get
+			// reports of their own, written first
+			repeat = reports[len(reports)-1]
+			l.reportRepeats(skip+1, traceId, reports[:len(reports)-1])
+		}
+	}
+	if !at.IsZero() {
+		ce.Time = at
+	}
+	if level == zapcore.PanicLevel || level == zapcore.FatalLevel {
+		ce = ce.After(ce.Entry, terminalHook{logger: l, code: l.fatalExitCode})
+	}
+	if l.stacktraceEnabled && level >= l.stacktraceLevel {
+		ce.Stack = formatStack(callerFrames(skip+1, l.maxStacktraceDepth))
+	}
+
+	if l.preEmit != nil {
+		fields = slices.Clip(fields) // Appends by the hook must not reach the caller's array
+		l.preEmit(levelFromZap(level), msg, &fields)
+	}
+
+	dynamic := l.prepareFields(fields)
+	if mustLog || l.fieldNamespace != "" {
+		dynamic = append(slices.Clip(l.fields), dynamic...)
+	}
+	zapFields := dynamic
+	if l.fieldNamespace != "" {
+		zapFields = nil // Added last, under the namespace
+	}
+	if l.component != "" {
+		zapFields = append(zapFields, zap.String("component", l.component))
+	}
+	if l.eventType != "" {
+		zapFields = append(zapFields, zap.String("event_type", l.eventType))
+	}
+	if len(l.tags) > 0 {
+		zapFields = append(zapFields, zap.Strings("tags", l.tags))
+	}
+	if traceId != "" {
+		zapFields = append(zapFields, zap.String("trace_id", traceId))
+	}
+	zapFields = append(zapFields, zap.Reflect("metadata", serializeMetadata(l.prepareMetadata(metadata), l.metadataSerializer))) // Reflect writes pre-encoded JSON as-is
+	if l.correlationID != "" {
+		zapFields = append(zapFields, zap.String("correlation_id", l.correlationID))
+	}
+	if l.includeSampleDecision {
+		zapFields = append(zapFields, zap.Bool("sampled", true))
+	}
+	if repeat.suppressed > 0 {
+		zapFields = append(zapFields,
+			zap.String("repeated_message", repeat.message),
+			zap.Int("repeats_suppressed", repeat.suppressed),
+		)
+	}
+
+	if len(l.promoteMetadataKeys) > 0 {
+		zapFields = append(zapFields, promoteMetadata(metadata, l.promoteMetadataKeys)...)
+	}
+
+	if l.includeUptime {
+		zapFields = append(zapFields, zap.Int64("uptime_ms", l.clock.Now().Sub(l.created).Milliseconds()))
+	}
+	if l.includeGoroutineID {
+		zapFields = append(zapFields, zap.Uint64("goroutine_id", goroutineID()))
+	}
+
+	// Add caller and function only if enabled for this level
+	if l.enableCaller && level >= l.callerMinLevel {
+		caller := getCaller(skip+1, l.callerTrimPrefix)
 		zapFields = append(zapFields,
 			zap.String("caller", fmt.Sprintf("%s:%d", caller.file, caller.line)),
 			zap.String("function", caller.function),
 		)
 	}
 
-	l.zapLogger.Fatal(msg, zapFields...)
+	if l.fieldNamespace != "" && slices.ContainsFunc(dynamic, writesField) {
+		zapFields = append(zapFields, zap.Namespace(l.fieldNamespace))
+		zapFields = append(zapFields, dynamic...)
+	}
+
+	ce.Write(zapFields...)
+}
+
+// writesField reports whether f writes anything; MustLog, for one, doesn't.
+func writesField(f zap.Field) bool {
+	return f.Type != zapcore.SkipType
+}
+
+// prepareFields converts user fields to zap fields, applying the logger's
+// field-level settings.
+func (l *Logger) prepareFields(fields []Field) []zap.Field {
+	zapFields := toZapFields(fields)
+	if l.allowedFieldKeys != nil {
+		zapFields = slices.DeleteFunc(zapFields, func(f zap.Field) bool {
+			if allowedField(f, l.allowedFieldKeys) {
+				return false
+			}
+			l.stats.DisallowedFields.Add(1)
+			return true
+		})
+	}
+	if l.omitEmptyFields {
+		zapFields = slices.DeleteFunc(zapFields, isEmptyField)
+	}
+	if l.largeNumbersAsString {
+		for i := range zapFields {
+			zapFields[i] = stringifyLargeNumber(zapFields[i])
+		}
+	}
+	if l.boolsAsStrings {
+		for i := range zapFields {
+			zapFields[i] = stringifyBool(zapFields[i])
+		}
+	}
+	return zapFields
+}
+
+// prepareMetadata applies log struct tags, AllowedFieldKeys, and
+// MaxMetadataDepth to metadata.
+func (l *Logger) prepareMetadata(metadata any) any {
+	metadata = redactMetadata(metadata)
+	if l.allowedFieldKeys != nil {
+		var dropped int
+		metadata, dropped = filterMetadataKeys(metadata, l.allowedFieldKeys)
+		l.stats.DisallowedFields.Add(uint64(dropped))
+	}
+	if l.maxMetadataDepth > 0 {
+		metadata = limitMetadataDepth(metadata, l.maxMetadataDepth)
+	}
+	return metadata
 }
 
 // Sync flushes any buffered log entries.
@@ -250,3 +771,60 @@ func (l *Logger) Fatal(traceId string, msg string, metadata any, fields ...Field
 func (l *Logger) Sync() error {
 	return l.zapLogger.Sync()
 }
+
+// ErrSyncTimeout is returned by SyncTimeout when the flush does not finish in time.
+var ErrSyncTimeout = errors.New("log: sync timed out")
+
+// SyncTimeout is like Sync but gives up after d, so a slow or hung output
+// cannot block shutdown indefinitely. On timeout it returns an error wrapping
+// ErrSyncTimeout; the flush keeps running in the background and its result
+// is discarded.
+//
+// Example:
+//
+//	if err := logger.SyncTimeout(5 * time.Second); errors.Is(err, log.ErrSyncTimeout) {
+//	    // Some entries may not have been written
+//	}
+func (l *Logger) SyncTimeout(d time.Duration) error {
+	done := make(chan error, 1) // Buffered so the flush can finish after a timeout
+	go func() {
+		done <- l.Sync()
+	}()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("%w after %s", ErrSyncTimeout, d)
+	}
+}
+
+// Drain flushes queued entries and returns the entries that could not be
+// written, for example because the output failed. A shutdown handler can spill
+// them somewhere else, such as a local file. Each returned entry is a complete
+// encoded line and is returned only once.
+//
+// Only async loggers (Config.Async) retain unwritten entries; for other
+// loggers Drain is equivalent to Sync and returns no entries.
+func (l *Logger) Drain() ([][]byte, error) {
+	return l.pipeline.Drain()
+}
+
+// Close flushes any buffered or queued entries and releases the resources held
+// by the output, such as async workers and open files. Close affects the logger
+// and every logger derived from it with With. Entries logged after Close are
+// still written, synchronously, where the output allows it.
+//
+// Example:
+//
+//	func main() {
+//	    logger, _ := log.New(log.Config{...})
+//	    defer logger.Close()
+//	    // ... application code
+//	}
+func (l *Logger) Close() error {
+	return l.pipeline.Close()
+}
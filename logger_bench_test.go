@@ -0,0 +1,135 @@
+package log_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func newBenchLogger(b *testing.B, cfg log.Config) *log.Logger {
+	b.Helper()
+
+	cfg.Service = "bench-service"
+	cfg.Env = "production"
+	cfg.Level = log.InfoLevel
+	if cfg.Output == "" {
+		cfg.Output = log.OutputFile
+		cfg.FilePath = filepath.Join(b.TempDir(), "bench.log")
+		cfg.MaxSizeMB = 1024
+	}
+
+	logger, err := log.New(cfg)
+	if err != nil {
+		b.Fatalf("failed to create logger: %v", err)
+	}
+	b.Cleanup(func() { logger.Close() })
+	return logger
+}
+
+func benchmarkInfo(b *testing.B, logger *log.Logger) {
+	metadata := map[string]any{"ip": "192.168.1.1", "method": "POST"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("req-123", "benchmark message", metadata,
+				log.String("user_id", "user-456"),
+				log.Int("response_code", 200),
+			)
+		}
+	})
+	b.StopTimer()
+	logger.Sync()
+}
+
+func BenchmarkLogger_Info_Sync(b *testing.B) {
+	benchmarkInfo(b, newBenchLogger(b, log.Config{}))
+}
+
+func BenchmarkLogger_Info_Async(b *testing.B) {
+	benchmarkInfo(b, newBenchLogger(b, log.Config{Async: true, AsyncWorkers: 4}))
+}
+
+// benchFields returns n distinct fields.
+func benchFields(n int) []log.Field {
+	fields := make([]log.Field, n)
+	for i := range fields {
+		fields[i] = log.Int(fmt.Sprintf("field_%d", i), i)
+	}
+	return fields
+}
+
+func BenchmarkLogger_Info_Fields(b *testing.B) {
+	for _, n := range []int{0, 3, 10} {
+		b.Run(fmt.Sprintf("fields=%d", n), func(b *testing.B) {
+			logger := newBenchLogger(b, log.Config{})
+			fields := benchFields(n)
+
+			b.ReportAllocs()
+			for b.Loop() {
+				logger.Info("req-123", "benchmark message", nil, fields...)
+			}
+		})
+	}
+}
+
+func BenchmarkLogger_With_Depth(b *testing.B) {
+	for _, depth := range []int{1, 3, 5} {
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			logger := newBenchLogger(b, log.Config{})
+			for i := range depth {
+				logger = logger.With(log.Int(fmt.Sprintf("bound_%d", i), i))
+			}
+
+			b.ReportAllocs()
+			for b.Loop() {
+				logger.Info("req-123", "benchmark message", nil, log.String("user_id", "user-456"))
+			}
+		})
+	}
+}
+
+func BenchmarkLogger_Info_Output(b *testing.B) {
+	b.Run("file", func(b *testing.B) {
+		logger := newBenchLogger(b, log.Config{})
+
+		b.ReportAllocs()
+		for b.Loop() {
+			logger.Info("req-123", "benchmark message", nil, log.String("user_id", "user-456"))
+		}
+	})
+
+	b.Run("stdout", func(b *testing.B) {
+		// Stdout is captured when the logger is built; point it at the null device
+		devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			b.Fatalf("failed to open %s: %v", os.DevNull, err)
+		}
+		stdout := os.Stdout
+		os.Stdout = devNull
+		logger := newBenchLogger(b, log.Config{Output: log.OutputStdout})
+		os.Stdout = stdout
+		b.Cleanup(func() { devNull.Close() })
+
+		b.ReportAllocs()
+		for b.Loop() {
+			logger.Info("req-123", "benchmark message", nil, log.String("user_id", "user-456"))
+		}
+	})
+}
+
+func BenchmarkLogger_Info_FieldSlice(b *testing.B) {
+	logger := newBenchLogger(b, log.Config{})
+
+	b.ReportAllocs()
+	for b.Loop() {
+		fs := log.NewFieldSlice(3)
+		fs.Append(log.String("user_id", "user-456"), log.Int("response_code", 200), log.Bool("cached", true))
+		logger.Info("req-123", "benchmark message", nil, fs.Fields...)
+		fs.Release()
+	}
+}
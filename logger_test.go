@@ -2,12 +2,19 @@ package log_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/glennprays/log"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestNew_ValidConfig(t *testing.T) {
@@ -760,6 +767,43 @@ func TestLogger_With_Metadata(t *testing.T) {
 	}
 }
 
+func TestLogger_Merge(t *testing.T) {
+	ch := make(chan []byte, 10)
+	logger, err := log.NewChannelLogger(log.Config{Service: "test-service", Env: "dev", Level: log.InfoLevel}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	base := logger.With(log.String("region", "eu-west-1"), log.String("tier", "global"))
+	request := logger.With(log.String("user_id", "user-456"), log.String("tier", "request"))
+
+	base.Merge(request).Info("req-123", "merged", nil)
+	line := <-ch
+	var entry map[string]any
+	if err := json.Unmarshal(line, &entry); err != nil {
+		t.Fatalf("log output is not valid JSON: %v", err)
+	}
+	if entry["region"] != "eu-west-1" || entry["user_id"] != "user-456" {
+		t.Errorf("expected fields from both loggers, got %v", entry)
+	}
+	if entry["tier"] != "request" || bytes.Count(line, []byte(`"tier"`)) != 1 {
+		t.Errorf("expected a single tier=request from the merged-in logger, got %s", line)
+	}
+	if bytes.Count(line, []byte(`"service"`)) != 1 {
+		t.Errorf("expected default fields once, got %s", line)
+	}
+
+	// Both sources are unchanged
+	base.Info("req-123", "base", nil)
+	if line := <-ch; bytes.Contains(line, []byte("user_id")) || !bytes.Contains(line, []byte(`"tier":"global"`)) {
+		t.Errorf("expected base logger unchanged, got %s", line)
+	}
+
+	if merged := base.Merge(logger); merged != base {
+		t.Error("expected merging a logger without bound fields to return the receiver")
+	}
+}
+
 func TestLogger_CallerDisabledByDefault(t *testing.T) {
 	tmpFile := "test_caller_disabled.log"
 	defer os.Remove(tmpFile)
@@ -898,3 +942,540 @@ func TestLogger_With_PreservesCallerSetting(t *testing.T) {
 		t.Error("child logger should preserve parent's EnableCaller setting")
 	}
 }
+
+func TestLogger_LogAt(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	eventTime := time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC)
+
+	logger.DebugAt(eventTime, "req-1", "replayed", nil)
+	logger.InfoAt(eventTime, "req-2", "replayed", nil)
+	logger.WarnAt(eventTime, "req-3", "replayed", nil)
+	logger.ErrorAt(eventTime, "req-4", "replayed", nil)
+
+	got := entries()
+	if len(got) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(got))
+	}
+	for i, logEntry := range got {
+		ts, _ := logEntry["timestamp"].(string)
+		parsed, err := time.Parse("2006-01-02T15:04:05.000Z0700", ts)
+		if err != nil {
+			t.Fatalf("entry %d: failed to parse timestamp %q: %v", i, ts, err)
+		}
+		if !parsed.Equal(eventTime) {
+			t.Errorf("entry %d: expected timestamp %v, got %v", i, eventTime, parsed)
+		}
+	}
+}
+
+func recurse(depth int, fn func()) {
+	if depth == 0 {
+		fn()
+		return
+	}
+	recurse(depth-1, fn)
+}
+
+func TestLogger_Stacktrace(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{StacktraceLevel: log.ErrorLevel})
+
+	logger.Warn("req-1", "below stacktrace level", nil)
+	logger.Error("req-2", "at stacktrace level", nil)
+
+	got := entries()
+	if _, exists := got[0]["stacktrace"]; exists {
+		t.Error("stacktrace should not be present below StacktraceLevel")
+	}
+	stack, _ := got[1]["stacktrace"].(string)
+	if !strings.HasPrefix(stack, "github.com/glennprays/log_test.TestLogger_Stacktrace\n\t") {
+		t.Errorf("stacktrace should start at the caller, got %q", stack)
+	}
+}
+
+func TestLogger_MaxStacktraceDepth(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{StacktraceLevel: log.ErrorLevel, MaxStacktraceDepth: 3})
+
+	recurse(50, func() { logger.Error("req-123", "deep", nil) })
+
+	stack, _ := entries()[0]["stacktrace"].(string)
+	lines := strings.Split(stack, "\n")
+	if len(lines) != 3*2+1 {
+		t.Fatalf("expected 3 frames and a marker, got %d lines: %q", len(lines), stack)
+	}
+	if lines[len(lines)-1] != "...truncated" {
+		t.Errorf("expected truncation marker, got %q", lines[len(lines)-1])
+	}
+}
+
+// hungOutput is a WriteSyncer whose Sync blocks until release is closed.
+type hungOutput struct {
+	release chan struct{}
+}
+
+func (h *hungOutput) Write(p []byte) (int, error) { return len(p), nil }
+
+func (h *hungOutput) Sync() error {
+	<-h.release
+	return nil
+}
+
+func TestLogger_SyncTimeout(t *testing.T) {
+	out := &hungOutput{release: make(chan struct{})}
+	log.RegisterOutput("test-hung", func(cfg log.Config) (log.WriteSyncer, error) {
+		return out, nil
+	})
+
+	logger, err := log.New(log.Config{
+		Service: "test-service",
+		Env:     "dev",
+		Level:   log.InfoLevel,
+		Output:  "test-hung",
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	err = logger.SyncTimeout(20 * time.Millisecond)
+	if !errors.Is(err, log.ErrSyncTimeout) {
+		t.Errorf("expected ErrSyncTimeout, got %v", err)
+	}
+
+	close(out.release)
+	if err := logger.SyncTimeout(time.Second); err != nil {
+		t.Errorf("expected sync to complete, got %v", err)
+	}
+}
+
+// fixedClock is a Clock that always returns the same time.
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+func TestLogger_Clock(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	logger, entries := newTestLogger(t, log.Config{Clock: fixedClock{now: now}})
+
+	logger.Info("req-1", "from clock", nil)
+	logger.InfoAt(now.Add(-time.Hour), "req-2", "explicit time", nil)
+
+	got := entries()
+	if got[0]["timestamp"] != "2025-01-15T10:30:00.000Z" {
+		t.Errorf("expected timestamp from clock, got %v", got[0]["timestamp"])
+	}
+	if got[1]["timestamp"] != "2025-01-15T09:30:00.000Z" {
+		t.Errorf("expected explicit timestamp to win over clock, got %v", got[1]["timestamp"])
+	}
+}
+
+func TestLogger_TimeUTC(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2025, 1, 15, 19, 30, 0, 0, tokyo)
+
+	logger, entries := newTestLogger(t, log.Config{Clock: fixedClock{now: now}})
+	logger.Info("req-1", "local", nil)
+	if got := entries()[0]["timestamp"]; got != "2025-01-15T19:30:00.000+0900" {
+		t.Errorf("expected local time by default, got %v", got)
+	}
+
+	logger, entries = newTestLogger(t, log.Config{Clock: fixedClock{now: now}, TimeUTC: true})
+	logger.Info("req-2", "utc", nil)
+	logger.InfoAt(now.Add(-time.Hour), "req-3", "explicit time", nil)
+	got := entries()
+	if got[0]["timestamp"] != "2025-01-15T10:30:00.000Z" {
+		t.Errorf("expected UTC timestamp, got %v", got[0]["timestamp"])
+	}
+	if got[1]["timestamp"] != "2025-01-15T09:30:00.000Z" {
+		t.Errorf("expected explicit timestamp in UTC, got %v", got[1]["timestamp"])
+	}
+}
+
+func TestLogger_PreEmit(t *testing.T) {
+	var calls []log.Level
+	logger, entries := newTestLogger(t, log.Config{
+		Level: log.InfoLevel,
+		PreEmit: func(level log.Level, msg string, fields *[]log.Field) {
+			calls = append(calls, level)
+			*fields = append(*fields, log.Int("msg_len", len(msg)))
+		},
+	})
+
+	logger.Debug("req-1", "filtered", nil)
+	logger.With(log.String("layer", "api")).Warn("req-2", "slow", nil, log.String("path", "/orders"))
+
+	if len(calls) != 1 || calls[0] != log.WarnLevel {
+		t.Errorf("expected one PreEmit call at warn level, got %v", calls)
+	}
+	logEntry := entries()[0]
+	for key, want := range map[string]any{
+		"msg_len":  float64(4),
+		"path":     "/orders",
+		"layer":    "api",
+		"trace_id": "req-2",
+	} {
+		if logEntry[key] != want {
+			t.Errorf("expected %s=%v, got %v", key, want, logEntry[key])
+		}
+	}
+}
+
+func TestLogger_CallerMinLevel(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{
+		EnableCaller:   true,
+		CallerMinLevel: log.WarnLevel,
+	})
+
+	logger.Info("req-1", "high volume", nil)
+	logger.Warn("req-2", "worth locating", nil)
+	logger.Error("req-3", "failed", nil)
+
+	got := entries()
+	for _, key := range []string{"caller", "function"} {
+		if _, exists := got[0][key]; exists {
+			t.Errorf("expected no %s below CallerMinLevel, got %v", key, got[0][key])
+		}
+	}
+	for _, logEntry := range got[1:] {
+		if caller, _ := logEntry["caller"].(string); !strings.HasPrefix(caller, "logger_test.go:") {
+			t.Errorf("expected caller at or above CallerMinLevel, got %v", logEntry["caller"])
+		}
+	}
+}
+
+func TestLogger_CallerTrimPrefix(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Base(wd)
+
+	tests := []struct {
+		name   string
+		prefix string
+		want   string
+	}{
+		{"parent directory", filepath.Dir(wd), dir + "/logger_test.go:"},
+		{"trailing slash", filepath.Dir(wd) + "/", dir + "/logger_test.go:"},
+		{"partial directory name", wd[:len(wd)-1], "logger_test.go:"},
+		{"unrelated prefix", "/nonexistent/path", "logger_test.go:"},
+		{"unset", "", "logger_test.go:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newTestLogger(t, log.Config{
+				EnableCaller:     true,
+				CallerTrimPrefix: tt.prefix,
+			})
+
+			logger.Info("req-1", "located", nil)
+			if caller, _ := entries()[0]["caller"].(string); !strings.HasPrefix(caller, tt.want) {
+				t.Errorf("expected caller starting with %q, got %q", tt.want, caller)
+			}
+		})
+	}
+}
+
+func TestLogger_IncludeGoroutineID(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{IncludeGoroutineID: true})
+
+	logger.Info("req-1", "main", nil)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("req-2", "worker", nil)
+	}()
+	<-done
+
+	got := entries()
+	mainID, _ := got[0]["goroutine_id"].(float64)
+	workerID, _ := got[1]["goroutine_id"].(float64)
+	if mainID <= 0 || workerID <= 0 {
+		t.Fatalf("expected positive goroutine IDs, got %v and %v", got[0]["goroutine_id"], got[1]["goroutine_id"])
+	}
+	if mainID == workerID {
+		t.Errorf("expected different goroutine IDs, both were %v", mainID)
+	}
+}
+
+func TestLogger_IncludeUptime(t *testing.T) {
+	clock := &steppingClock{now: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC), step: time.Second}
+	logger, entries := newTestLogger(t, log.Config{IncludeUptime: true, Clock: clock})
+
+	child := logger.With(log.String("layer", "api"))
+	logger.Info("req-1", "parent", nil)
+	child.Info("req-2", "child", nil)
+
+	got := entries()
+	parentUptime, _ := got[0]["uptime_ms"].(float64)
+	childUptime, _ := got[1]["uptime_ms"].(float64)
+	if parentUptime <= 0 {
+		t.Fatalf("expected positive uptime_ms, got %v", got[0]["uptime_ms"])
+	}
+	if childUptime <= parentUptime {
+		t.Errorf("expected child uptime to continue from the parent's creation time, got %v then %v", parentUptime, childUptime)
+	}
+}
+
+func TestNewWithCore(t *testing.T) {
+	core, observed := observer.New(zapcore.DebugLevel)
+	logger, err := log.NewWithCore(log.Config{
+		Service: "test-service",
+		Env:     "dev",
+		Level:   log.InfoLevel,
+		Version: "1.4.2",
+	}, core)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.Debug("req-123", "below level", nil)
+	logger.Component("billing").With(log.String("user_id", "user-456")).Info("req-123", "charged", map[string]any{"amount": 42})
+
+	entries := observed.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expected one entry at or above the configured level, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	want := map[string]any{
+		"service":   "test-service",
+		"env":       "dev",
+		"version":   "1.4.2",
+		"component": "billing",
+		"user_id":   "user-456",
+		"trace_id":  "req-123",
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("expected %s=%v, got %v", key, value, fields[key])
+		}
+	}
+	if _, exists := fields["metadata"]; !exists {
+		t.Errorf("expected metadata field, got %v", fields)
+	}
+}
+
+func TestLogger_Tee(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "app.log")
+	logger, err := log.New(log.Config{
+		Service:  "test-service",
+		Env:      "dev",
+		Level:    log.InfoLevel,
+		Output:   log.OutputFile,
+		FilePath: filePath,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	core, observed := observer.New(zapcore.DebugLevel)
+	teed := logger.With(log.String("layer", "api")).Tee(core)
+	teed.With(log.String("order_id", "ord-1")).Info("req-123", "order placed", nil)
+	teed.Info("req-123", "order audited", nil, log.MustLog())
+	teed.Debug("req-123", "below level", nil)
+	logger.Info("req-123", "parent only", nil)
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	entries := observed.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 observed entries, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if entries[0].Message != "order placed" || fields["service"] != "test-service" || fields["layer"] != "api" || fields["order_id"] != "ord-1" || fields["trace_id"] != "req-123" {
+		t.Errorf("expected the observed entry to have all fields, got %s %v", entries[0].Message, fields)
+	}
+	if entries[1].Message != "order audited" {
+		t.Errorf("expected MustLog entries observed once, got %s", entries[1].Message)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if lines := strings.Count(string(content), "\n"); lines != 3 {
+		t.Errorf("expected the file to keep receiving all 3 entries, got %d:\n%s", lines, content)
+	}
+}
+
+func TestLogger_FatalFlushesBeforeExit(t *testing.T) {
+	out := &memoryOutput{}
+	log.RegisterOutput("test-fatal-flush", func(log.Config) (log.WriteSyncer, error) {
+		return out, nil
+	})
+	logger, err := log.New(log.Config{
+		Service: "test-service",
+		Env:     "dev",
+		Level:   log.InfoLevel,
+		Output:  "test-fatal-flush",
+		Async:   true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	var atExit [][]byte
+	defer log.SetExit(func(int) {
+		out.mu.Lock()
+		defer out.mu.Unlock()
+		atExit = slices.Clone(out.entries)
+	})()
+
+	for i := range 500 {
+		logger.Info("req-123", "queued", nil, log.Int("seq", i))
+	}
+	logger.Fatal("req-123", "shutting down", nil, log.MustLog()) // Written synchronously, past the queue
+
+	if len(atExit) != 501 {
+		t.Fatalf("expected 501 entries written before exiting, got %d", len(atExit))
+	}
+	var lastQueued, fatal bool
+	for _, line := range atExit {
+		var logEntry map[string]any
+		if err := json.Unmarshal(line, &logEntry); err != nil {
+			t.Fatalf("entry is not valid JSON: %v", err)
+		}
+		lastQueued = lastQueued || logEntry["seq"] == float64(499)
+		fatal = fatal || logEntry["level"] == "fatal"
+	}
+	if !lastQueued || !fatal {
+		t.Errorf("expected the last queued entry and the fatal entry before exiting, got last queued %v, fatal %v", lastQueued, fatal)
+	}
+}
+
+func TestLogger_Panic(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	defer func() {
+		if got := recover(); got != "invariant violated" {
+			t.Errorf("expected panic with the message, got %v", got)
+		}
+		got := entries()
+		if len(got) != 1 || got[0]["level"] != "panic" || got[0]["message"] != "invariant violated" {
+			t.Errorf("expected the panic entry written before panicking, got %v", got)
+		}
+	}()
+	logger.Panic("req-123", "invariant violated", nil)
+}
+
+func TestLogger_FatalExitCode(t *testing.T) {
+	var codes []int
+	defer log.SetExit(func(code int) { codes = append(codes, code) })()
+
+	logger, entries := newTestLogger(t, log.Config{FatalExitCode: 3})
+	child := logger.With(log.String("layer", "api"))
+
+	logger.Fatal("req-1", "default code", nil)
+	child.Fatal("req-2", "child", nil)
+	logger.FatalCode(78, "req-3", "explicit code", nil)
+	logger.Fatal("req-4", "default again", nil)
+
+	if want := []int{3, 3, 78, 3}; !slices.Equal(codes, want) {
+		t.Errorf("expected exit codes %v, got %v", want, codes)
+	}
+	if got := entries(); len(got) != 4 || got[2]["message"] != "explicit code" || got[2]["level"] != "fatal" {
+		t.Errorf("expected 4 fatal entries written before exiting, got %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for exit code 0")
+		}
+	}()
+	logger.FatalCode(0, "req-5", "invalid", nil)
+}
+
+func TestLogger_FatalUnlessCancelled(t *testing.T) {
+	var codes []int
+	defer log.SetExit(func(code int) { codes = append(codes, code) })()
+
+	logger, entries := newTestLogger(t, log.Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	logger.FatalUnlessCancelled(ctx, "req-1", "serve failed", nil)
+	if len(codes) != 1 {
+		t.Fatalf("expected exit while the context is live, got %v", codes)
+	}
+
+	cancel()
+	logger.FatalUnlessCancelled(ctx, "req-2", "serve failed", nil)
+	if len(codes) != 1 {
+		t.Errorf("expected no exit after cancellation, got %v", codes)
+	}
+
+	got := entries()
+	if got[0]["level"] != "fatal" || got[0]["context_error"] != nil {
+		t.Errorf("expected a fatal entry without context_error, got %v", got[0])
+	}
+	if got[1]["level"] != "error" || got[1]["context_error"] != "context canceled" {
+		t.Errorf("expected an error entry with context_error, got %v", got[1])
+	}
+}
+
+func TestConfig_FieldNamespace(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{FieldNamespace: "data"})
+	child := logger.With(log.String("user_id", "user-456")).Component("billing")
+
+	child.Info("req-123", "charged", nil, log.Int("amount", 42))
+	logger.Info("req-456", "no fields", nil, log.MustLog())
+
+	got := entries()
+	data, ok := got[0]["data"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected fields nested under data, got %v", got[0])
+	}
+	if data["user_id"] != "user-456" || data["amount"] != float64(42) {
+		t.Errorf("expected bound and per-call fields in data, got %v", data)
+	}
+	if got[0]["user_id"] != nil || got[0]["trace_id"] != "req-123" || got[0]["component"] != "billing" || got[0]["service"] != "test-service" {
+		t.Errorf("expected only reserved fields at the top level, got %v", got[0])
+	}
+	if _, ok := got[1]["data"]; ok {
+		t.Errorf("expected no data object without fields, got %v", got[1])
+	}
+
+	cfg := log.Config{Service: "test-service", Env: "dev", Level: log.InfoLevel, Output: log.OutputStdout, FieldNamespace: "message"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for reserved field namespace, got nil")
+	}
+}
+
+func TestLogger_SetLevel(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{Level: log.InfoLevel})
+	child := logger.With(log.String("layer", "api"))
+
+	child.Debug("req-1", "hidden", nil)
+	if err := logger.SetLevel(log.DebugLevel); err != nil {
+		t.Fatalf("SetLevel returned error: %v", err)
+	}
+	child.Debug("req-2", "shown", nil)
+
+	if got := child.GetLevel(); got != log.DebugLevel {
+		t.Errorf("expected the child to share the level, got %s", got)
+	}
+	if got := entries(); len(got) != 1 || got[0]["message"] != "shown" {
+		t.Errorf("expected only the entry after SetLevel, got %v", got)
+	}
+
+	if err := child.SetLevel("verbose"); err == nil {
+		t.Error("expected error for invalid level, got nil")
+	}
+	if got := logger.GetLevel(); got != log.DebugLevel {
+		t.Errorf("expected an invalid level to leave the level unchanged, got %s", got)
+	}
+
+	for _, level := range []log.Level{log.DebugLevel, log.InfoLevel, log.WarnLevel, log.ErrorLevel, log.PanicLevel, log.FatalLevel} {
+		if err := logger.SetLevel(level); err != nil {
+			t.Fatalf("SetLevel(%s) returned error: %v", level, err)
+		}
+		if got := logger.GetLevel(); got != level {
+			t.Errorf("expected GetLevel to report %s, got %s", level, got)
+		}
+	}
+	if err := logger.SetLevel("WARNING"); err != nil || logger.GetLevel() != log.WarnLevel {
+		t.Errorf("expected WARNING to be reported as warn, got %s (err: %v)", logger.GetLevel(), err)
+	}
+}
@@ -0,0 +1,154 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+)
+
+// promotedMetadataPrefix is prepended to metadata keys copied to the top level.
+const promotedMetadataPrefix = "meta_"
+
+// promoteMetadata copies the configured keys from map metadata to top-level
+// fields named meta_<key>. Keys that are missing, and metadata that is not a
+// map with string keys, are ignored.
+func promoteMetadata(metadata any, keys []string) []zap.Field {
+	var fields []zap.Field
+	switch m := metadata.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		for _, key := range keys {
+			if value, ok := m[key]; ok {
+				fields = append(fields, zap.Any(promotedMetadataPrefix+key, value))
+			}
+		}
+	case map[string]string:
+		for _, key := range keys {
+			if value, ok := m[key]; ok {
+				fields = append(fields, zap.String(promotedMetadataPrefix+key, value))
+			}
+		}
+	default:
+		v := reflect.ValueOf(metadata)
+		if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		for _, key := range keys {
+			value := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+			if value.IsValid() {
+				fields = append(fields, zap.Any(promotedMetadataPrefix+key, value.Interface()))
+			}
+		}
+	}
+	return fields
+}
+
+// serializeMetadata encodes composite metadata to JSON ahead of time so a value
+// that cannot be marshaled, such as a channel, a func, or a cyclic structure,
+// is replaced by a placeholder object instead of producing a broken entry:
+//
+//	{"_error": "metadata not serializable", "_type": "chan int"}
+//
+// Scalar metadata is returned unchanged. A non-nil serializer replaces the
+// default encoding for all metadata.
+func serializeMetadata(metadata any, serializer func(any) (json.RawMessage, error)) any {
+	if serializer != nil {
+		raw, err := serializer(metadata)
+		if err != nil || (len(raw) > 0 && !json.Valid(raw)) {
+			return unserializableMetadata(metadata)
+		}
+		if len(raw) == 0 {
+			return nil
+		}
+		return raw
+	}
+
+	switch metadata.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return metadata
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // Match zap's encoding
+	if err := enc.Encode(metadata); err != nil {
+		return unserializableMetadata(metadata)
+	}
+	return json.RawMessage(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+// unserializableMetadata returns the placeholder logged in place of metadata
+// that cannot be serialized.
+func unserializableMetadata(metadata any) map[string]string {
+	return map[string]string{
+		"_error": "metadata not serializable",
+		"_type":  fmt.Sprintf("%T", metadata),
+	}
+}
+
+// depthTruncatedMarker replaces metadata nested deeper than MaxMetadataDepth.
+var depthTruncatedMarker = map[string]any{"_depth_truncated": true}
+
+// limitMetadataDepth replaces maps, slices, and structs nested more than
+// maxDepth levels deep in metadata with depthTruncatedMarker; metadata itself
+// is level 1. Metadata is walked in its JSON form, so struct fields follow
+// their json tags. Metadata within the limit, and metadata that cannot be
+// encoded, is returned unchanged.
+func limitMetadataDepth(metadata any, maxDepth int) any {
+	switch metadata.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return metadata
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return metadata // serializeMetadata reports it
+	}
+	dec := json.NewDecoder(bytes.NewReader(encoded))
+	dec.UseNumber() // Keep large integers exact
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return metadata
+	}
+
+	truncated, changed := truncateDepth(decoded, 1, maxDepth)
+	if !changed {
+		return metadata
+	}
+	return truncated
+}
+
+// truncateDepth implements limitMetadataDepth for decoded JSON value v at
+// depth, reporting whether anything was replaced.
+func truncateDepth(v any, depth, maxDepth int) (any, bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		if depth > maxDepth {
+			return depthTruncatedMarker, true
+		}
+		changed := false
+		for key, value := range v {
+			var c bool
+			v[key], c = truncateDepth(value, depth+1, maxDepth)
+			changed = changed || c
+		}
+		return v, changed
+	case []any:
+		if depth > maxDepth {
+			return depthTruncatedMarker, true
+		}
+		changed := false
+		for i, value := range v {
+			var c bool
+			v[i], c = truncateDepth(value, depth+1, maxDepth)
+			changed = changed || c
+		}
+		return v, changed
+	default:
+		return v, false
+	}
+}
@@ -0,0 +1,320 @@
+package log_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func TestConfig_PromoteMetadataKeys(t *testing.T) {
+	type labels map[string]string
+
+	logger, entries := newTestLogger(t, log.Config{PromoteMetadataKeys: []string{"user_id", "tenant"}})
+
+	logger.Info("req-1", "any map", map[string]any{"user_id": "user-456", "ip": "192.168.1.1"})
+	logger.Info("req-2", "string map", map[string]string{"tenant": "acme"})
+	logger.Info("req-3", "named map", labels{"user_id": "user-789"})
+	logger.Info("req-4", "scalar", "not a map")
+
+	got := entries()
+
+	if got[0]["meta_user_id"] != "user-456" {
+		t.Errorf("expected meta_user_id=user-456, got %v", got[0]["meta_user_id"])
+	}
+	if _, exists := got[0]["meta_ip"]; exists {
+		t.Error("keys not listed in PromoteMetadataKeys should not be promoted")
+	}
+	metadata, _ := got[0]["metadata"].(map[string]any)
+	if metadata["user_id"] != "user-456" || metadata["ip"] != "192.168.1.1" {
+		t.Errorf("full metadata should still be nested, got %v", got[0]["metadata"])
+	}
+
+	if got[1]["meta_tenant"] != "acme" {
+		t.Errorf("expected meta_tenant=acme, got %v", got[1]["meta_tenant"])
+	}
+	if got[2]["meta_user_id"] != "user-789" {
+		t.Errorf("expected meta_user_id=user-789, got %v", got[2]["meta_user_id"])
+	}
+	if got[3]["metadata"] != "not a map" {
+		t.Errorf("expected scalar metadata unchanged, got %v", got[3]["metadata"])
+	}
+}
+
+func TestLogger_ScalarMetadata(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	logger.Info("req-1", "string", "checkout")
+	logger.Info("req-2", "int", 42)
+	logger.Info("req-3", "bool", true)
+
+	got := entries()
+	for i, want := range []any{"checkout", float64(42), true} {
+		if got[i]["metadata"] != want {
+			t.Errorf("entry %d: expected metadata=%v, got %v", i, want, got[i]["metadata"])
+		}
+	}
+}
+
+func TestLogger_InfoMeta(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{EnableCaller: true})
+	logger.InfoMeta("req-123", "cache hit", "cache_key", "user:456", log.String("user_id", "user-456"))
+
+	logEntry := entries()[0]
+	metadata, ok := logEntry["metadata"].(map[string]any)
+	if !ok || len(metadata) != 1 || metadata["cache_key"] != "user:456" {
+		t.Errorf("expected metadata={cache_key: user:456}, got %v", logEntry["metadata"])
+	}
+	if logEntry["level"] != "info" || logEntry["user_id"] != "user-456" {
+		t.Errorf("expected info entry with user_id field, got %v", logEntry)
+	}
+	if caller, _ := logEntry["caller"].(string); !strings.HasPrefix(caller, "metadata_test.go:") {
+		t.Errorf("caller should point to metadata_test.go, got %s", caller)
+	}
+}
+
+type credentials struct {
+	APIKey string `json:"api_key" log:"redact"`
+}
+
+type signupMetadata struct {
+	Email    string       `json:"email"`
+	Password string       `json:"password" log:"-"`
+	Token    string       `json:"token" log:"redact"`
+	Plan     string       `json:"plan,omitempty"`
+	Upstream *credentials `json:"upstream"`
+	Internal string       `json:"-"`
+}
+
+func TestLogger_MetadataStructTags(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Info("req-123", "signup", &signupMetadata{
+		Email:    "a@example.com",
+		Password: "hunter2",
+		Token:    "tok-123",
+		Upstream: &credentials{APIKey: "key-456"},
+		Internal: "hidden",
+	})
+
+	metadata, ok := entries()[0]["metadata"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected metadata object, got %v", entries()[0]["metadata"])
+	}
+	if metadata["email"] != "a@example.com" {
+		t.Errorf("expected email to be kept, got %v", metadata["email"])
+	}
+	if metadata["token"] != "[REDACTED]" {
+		t.Errorf("expected token to be redacted, got %v", metadata["token"])
+	}
+	for _, key := range []string{"password", "plan", "Internal", "Password"} {
+		if _, exists := metadata[key]; exists {
+			t.Errorf("expected %s to be omitted, got %v", key, metadata[key])
+		}
+	}
+	upstream, _ := metadata["upstream"].(map[string]any)
+	if upstream["api_key"] != "[REDACTED]" {
+		t.Errorf("expected nested api_key to be redacted, got %v", metadata["upstream"])
+	}
+}
+
+type linkedNode struct {
+	Name   string      `json:"name"`
+	Secret string      `json:"secret" log:"redact"`
+	Next   *linkedNode `json:"next"`
+}
+
+func TestLogger_MetadataStructTagsCycle(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	node := &linkedNode{Name: "a", Secret: "s"}
+	node.Next = node
+	logger.Info("req-123", "cyclic", node)
+
+	shared := &linkedNode{Name: "shared", Secret: "s"}
+	logger.Info("req-123", "shared", struct {
+		First  *linkedNode `json:"first"`
+		Second *linkedNode `json:"second"`
+	}{shared, shared})
+
+	type tree struct {
+		Secret   string          `json:"secret" log:"redact"`
+		Children map[string]tree `json:"children"`
+	}
+	children := map[string]tree{}
+	children["self"] = tree{Secret: "s", Children: children}
+	logger.Info("req-123", "cyclic map", tree{Children: children})
+
+	got := entries()
+	if metadata, _ := got[2]["metadata"].(map[string]any); metadata["_error"] != "metadata not serializable" {
+		t.Errorf("expected placeholder for metadata with a cyclic map, got %v", got[2]["metadata"])
+	}
+	metadata, _ := got[0]["metadata"].(map[string]any)
+	if metadata["_error"] != "metadata not serializable" {
+		t.Errorf("expected placeholder for cyclic metadata, got %v", got[0]["metadata"])
+	}
+	metadata, _ = got[1]["metadata"].(map[string]any)
+	second, _ := metadata["second"].(map[string]any)
+	if second["name"] != "shared" || second["secret"] != "[REDACTED]" {
+		t.Errorf("expected a pointer seen twice without a cycle to be logged, got %v", got[1]["metadata"])
+	}
+}
+
+type loginUser struct {
+	Name     string `json:"name"`
+	Password string `json:"password" log:"redact"`
+}
+
+func TestLogger_MetadataStructTagsInCollections(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	users := []loginUser{{Name: "ada", Password: "hunter2"}}
+
+	logger.Info("req-123", "nested", struct {
+		Users []loginUser            `json:"users"`
+		ByID  map[int]*loginUser     `json:"by_id"`
+		Pair  [1]loginUser           `json:"pair"`
+		Teams map[string][]loginUser `json:"teams"`
+	}{users, map[int]*loginUser{7: &users[0]}, [1]loginUser{users[0]}, map[string][]loginUser{"core": users}})
+	logger.Info("req-123", "top-level", users)
+
+	got := entries()
+	if strings.Contains(fmt.Sprint(got), "hunter2") {
+		t.Errorf("expected passwords in collections to be redacted, got %v", got)
+	}
+	metadata, _ := got[0]["metadata"].(map[string]any)
+	byID, _ := metadata["by_id"].(map[string]any)
+	if user, _ := byID["7"].(map[string]any); user["name"] != "ada" || user["password"] != "[REDACTED]" {
+		t.Errorf("expected map value to be redacted, got %v", metadata["by_id"])
+	}
+	list, _ := got[1]["metadata"].([]any)
+	if len(list) != 1 || list[0].(map[string]any)["password"] != "[REDACTED]" {
+		t.Errorf("expected slice metadata to be redacted, got %v", got[1]["metadata"])
+	}
+}
+
+func TestLogger_MetadataWithoutTagsUnchanged(t *testing.T) {
+	type plain struct {
+		At   time.Time `json:"at"`
+		Name string    `json:"name"`
+	}
+	at := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Info("req-123", "plain", plain{At: at, Name: "x"})
+
+	metadata, _ := entries()[0]["metadata"].(map[string]any)
+	if metadata["at"] != "2025-01-15T10:30:00Z" || metadata["name"] != "x" {
+		t.Errorf("expected untagged struct to be marshaled as JSON, got %v", entries()[0]["metadata"])
+	}
+}
+
+func TestLogger_UnserializableMetadata(t *testing.T) {
+	cyclic := map[string]any{"name": "loop"}
+	cyclic["self"] = cyclic
+
+	tests := []struct {
+		name     string
+		metadata any
+		wantType string
+	}{
+		{name: "channel", metadata: map[string]any{"events": make(chan int)}, wantType: "map[string]interface {}"},
+		{name: "func", metadata: func() {}, wantType: "func()"},
+		{name: "cyclic map", metadata: cyclic, wantType: "map[string]interface {}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newTestLogger(t, log.Config{})
+			logger.Info("req-123", "bad metadata", tt.metadata, log.String("user_id", "u-1"))
+
+			logEntry := entries()[0]
+			metadata, ok := logEntry["metadata"].(map[string]any)
+			if !ok || metadata["_error"] != "metadata not serializable" || metadata["_type"] != tt.wantType {
+				t.Errorf("expected placeholder metadata with _type=%s, got %v", tt.wantType, logEntry["metadata"])
+			}
+			if logEntry["user_id"] != "u-1" || logEntry["message"] != "bad metadata" {
+				t.Errorf("expected the rest of the entry to be intact, got %v", logEntry)
+			}
+			if _, exists := logEntry["metadataError"]; exists {
+				t.Errorf("expected no zap error field, got %v", logEntry["metadataError"])
+			}
+		})
+	}
+}
+
+func TestConfig_MetadataSerializer(t *testing.T) {
+	asString := func(metadata any) (json.RawMessage, error) {
+		if metadata == nil {
+			return nil, nil
+		}
+		encoded, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(encoded))
+	}
+
+	logger, entries := newTestLogger(t, log.Config{MetadataSerializer: asString})
+	logger.Info("req-1", "escaped", map[string]any{"ip": "10.0.0.1"})
+	logger.Info("req-2", "nil", nil)
+
+	got := entries()
+	if got[0]["metadata"] != `{"ip":"10.0.0.1"}` {
+		t.Errorf("expected metadata as an escaped string, got %#v", got[0]["metadata"])
+	}
+	if got[1]["metadata"] != nil {
+		t.Errorf("expected null metadata for an empty result, got %v", got[1]["metadata"])
+	}
+}
+
+func TestConfig_MetadataSerializerFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		serializer func(any) (json.RawMessage, error)
+	}{
+		{name: "error", serializer: func(any) (json.RawMessage, error) { return nil, errors.New("boom") }},
+		{name: "invalid JSON", serializer: func(any) (json.RawMessage, error) { return json.RawMessage("{"), nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, entries := newTestLogger(t, log.Config{MetadataSerializer: tt.serializer})
+			logger.Info("req-123", "bad metadata", map[string]any{"ip": "10.0.0.1"})
+
+			metadata, ok := entries()[0]["metadata"].(map[string]any)
+			if !ok || metadata["_error"] != "metadata not serializable" {
+				t.Errorf("expected placeholder metadata, got %v", metadata)
+			}
+		})
+	}
+}
+
+func TestConfig_MaxMetadataDepth(t *testing.T) {
+	nested := map[string]any{"level": 10}
+	for level := 9; level >= 1; level-- {
+		nested = map[string]any{"level": level, "child": nested}
+	}
+
+	logger, entries := newTestLogger(t, log.Config{MaxMetadataDepth: 3})
+	logger.Info("req-1", "deep", nested)
+	logger.Info("req-2", "shallow", map[string]any{"list": []any{1, 2}, "n": 12345678901234567})
+
+	got := entries()
+	level1, _ := got[0]["metadata"].(map[string]any)
+	level2, _ := level1["child"].(map[string]any)
+	level3, _ := level2["child"].(map[string]any)
+	if level1["level"] != float64(1) || level2["level"] != float64(2) || level3["level"] != float64(3) {
+		t.Fatalf("expected the first 3 levels intact, got %v", got[0]["metadata"])
+	}
+	if marker, _ := level3["child"].(map[string]any); len(marker) != 1 || marker["_depth_truncated"] != true {
+		t.Errorf("expected level 4 replaced by the truncation marker, got %v", level3["child"])
+	}
+
+	shallow, _ := got[1]["metadata"].(map[string]any)
+	if list, _ := shallow["list"].([]any); len(list) != 2 || shallow["n"] != float64(12345678901234567) {
+		t.Errorf("expected metadata within the limit unchanged, got %v", got[1]["metadata"])
+	}
+}
@@ -0,0 +1,47 @@
+package log
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// metricTags encodes metric tags as an object with its keys in lexical order.
+type metricTags map[string]string
+
+func (m metricTags) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		enc.AddString(key, m[key])
+	}
+	return nil
+}
+
+// Metric logs a measurement as an info entry following the log-based metrics
+// convention, for services without a metrics system whose entries are turned
+// into metrics downstream. Every metric entry has the same shape; tags may be
+// nil and are written with their keys in lexical order:
+//
+//	"message": "metric", "log_type": "metric", "metric_name": "queue.depth", "metric_value": 42, "metric_unit": "messages", "metric_tags": {"queue": "orders"}
+//
+// Example:
+//
+//	logger.Metric(traceID, "checkout.latency", float64(elapsed.Milliseconds()), "ms", map[string]string{"region": "eu-west-1"})
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID),
+// or if name is empty.
+func (l *Logger) Metric(traceId, name string, value float64, unit string, tags map[string]string) {
+	if strings.TrimSpace(name) == "" {
+		panic("log: metric name cannot be empty")
+	}
+	l.log(zapcore.InfoLevel, 1, time.Time{}, traceId, "metric", nil, []Field{
+		String("log_type", "metric"),
+		String("metric_name", name),
+		Float64("metric_value", value),
+		String("metric_unit", unit),
+		{zapField: zap.Object("metric_tags", metricTags(tags))},
+	})
+}
@@ -0,0 +1,39 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_Metric(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	logger.Metric("req-123", "queue.depth", 42, "messages", map[string]string{"queue": "orders"})
+	logger.Metric("req-123", "uptime", 1.5, "s", nil)
+
+	got := entries()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(got))
+	}
+	entry := got[0]
+	if entry["level"] != "info" || entry["message"] != "metric" || entry["log_type"] != "metric" {
+		t.Errorf("expected an info metric entry, got %v", entry)
+	}
+	if entry["metric_name"] != "queue.depth" || entry["metric_value"] != float64(42) || entry["metric_unit"] != "messages" {
+		t.Errorf("unexpected metric fields %v", entry)
+	}
+	if tags, ok := entry["metric_tags"].(map[string]any); !ok || tags["queue"] != "orders" {
+		t.Errorf("expected metric_tags object, got %v", entry["metric_tags"])
+	}
+	if tags, ok := got[1]["metric_tags"].(map[string]any); !ok || len(tags) != 0 {
+		t.Errorf("expected empty metric_tags for nil tags, got %v", got[1]["metric_tags"])
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for empty metric name")
+		}
+	}()
+	logger.Metric("req-123", "", 1, "", nil)
+}
@@ -0,0 +1,124 @@
+package log
+
+import (
+	"crypto/rand"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultTraceIDHeader is the request header Middleware reads the traceId
+// from when MiddlewareOptions.TraceIDHeader is empty.
+const DefaultTraceIDHeader = "X-Request-ID"
+
+// MiddlewareOptions configures Logger.Middleware.
+type MiddlewareOptions struct {
+	// TraceIDHeader is the request header holding the traceId. Requests
+	// without it get a random traceId.
+	// Default: DefaultTraceIDHeader
+	TraceIDHeader string
+
+	// DisableRecovery lets panics in downstream handlers propagate instead of
+	// recovering them, for servers that recover panics themselves.
+	// Default: false (panics are recovered)
+	DisableRecovery bool
+}
+
+// Middleware returns HTTP middleware that stores a request logger in the
+// request context, for retrieval with FromContext. The request logger uses
+// the request's traceId as its default (see WithDefaultTraceID), so handlers
+// can pass an empty traceId. The traceId is also stored in the context (see
+// ContextWithTraceID), for the Ctx methods of other loggers.
+//
+// Unless DisableRecovery is set, the middleware also recovers panics in
+// downstream handlers. It logs them at error level with the panic value, the
+// stacktrace of the panic, and the request's method and path, then responds
+// with 500 Internal Server Error if the handler had not started a response.
+// As with net/http, a panic with http.ErrAbortHandler is not logged and
+// still aborts the request.
+//
+//	"message": "panic recovered", "panic": "index out of range", "method": "GET", "path": "/orders/42", "stacktrace": "..."
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	mux.HandleFunc("GET /orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+//	    log.FromContext(r.Context()).Info("", "loading order", nil)
+//	})
+//	http.ListenAndServe(":8080", logger.Middleware(log.MiddlewareOptions{})(mux))
+func (l *Logger) Middleware(opts MiddlewareOptions) func(http.Handler) http.Handler {
+	if opts.TraceIDHeader == "" {
+		opts.TraceIDHeader = DefaultTraceIDHeader
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceId := r.Header.Get(opts.TraceIDHeader)
+			if traceId == "" {
+				traceId = rand.Text()
+			}
+			requestLogger := l.WithDefaultTraceID(traceId)
+			r = r.WithContext(IntoContext(ContextWithTraceID(r.Context(), traceId), requestLogger))
+
+			if opts.DisableRecovery {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rw := &responseWriter{ResponseWriter: w}
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+				requestLogger.logPanic(traceId, recovered, r)
+				if !rw.wroteHeader {
+					http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// logPanic logs a panic recovered by Middleware with the stacktrace of the
+// panic, regardless of Config.StacktraceLevel. It must be called from the
+// deferred function that recovered the panic, so the stack still holds the
+// panicking frames; the skip of 3 steps over logPanic, the deferred function,
+// and runtime.gopanic.
+func (l *Logger) logPanic(traceId string, recovered any, r *http.Request) {
+	withStack := *l
+	withStack.stacktraceEnabled = true
+	withStack.stacktraceLevel = zapcore.ErrorLevel
+	withStack.log(zapcore.ErrorLevel, 3, time.Time{}, traceId, "panic recovered", nil, []Field{
+		Any("panic", recovered),
+		String("method", r.Method),
+		String("path", r.URL.Path),
+	})
+}
+
+// responseWriter records whether a response was started, so a recovered
+// panic only writes an error response if the handler had not.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
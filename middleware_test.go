@@ -0,0 +1,91 @@
+package log_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_Middleware(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	handler := logger.Middleware(log.MiddlewareOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.FromContext(r.Context()).Info("", "loading order", nil)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/43", nil))
+
+	got := entries()
+	if len(got) != 2 || got[0]["trace_id"] != "req-123" {
+		t.Fatalf("expected the request logger to default to the header traceId, got %v", got)
+	}
+	if generated, _ := got[1]["trace_id"].(string); generated == "" || generated == "req-123" {
+		t.Errorf("expected a generated traceId without the header, got %v", got[1]["trace_id"])
+	}
+}
+
+func TestLogger_MiddlewareRecoversPanics(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	handler := logger.Middleware(log.MiddlewareOptions{TraceIDHeader: "X-Trace"})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("order not loaded")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set("X-Trace", "req-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", rec.Code)
+	}
+	got := entries()
+	if len(got) != 1 {
+		t.Fatalf("expected one entry for the panic, got %v", got)
+	}
+	logEntry := got[0]
+	if logEntry["level"] != "error" || logEntry["message"] != "panic recovered" || logEntry["trace_id"] != "req-123" {
+		t.Errorf("expected an error entry for the request, got %v", logEntry)
+	}
+	if logEntry["panic"] != "order not loaded" || logEntry["method"] != "POST" || logEntry["path"] != "/orders" {
+		t.Errorf("expected panic value, method, and path fields, got %v", logEntry)
+	}
+	if stack, _ := logEntry["stacktrace"].(string); !strings.HasPrefix(stack, "github.com/glennprays/log_test.TestLogger_MiddlewareRecoversPanics.func1") {
+		t.Errorf("expected the stacktrace to start at the panicking handler, got %q", stack)
+	}
+}
+
+func TestLogger_MiddlewareStartedResponse(t *testing.T) {
+	logger, _ := newTestLogger(t, log.Config{})
+	handler := logger.Middleware(log.MiddlewareOptions{})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("after responding")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusAccepted || rec.Body.Len() != 0 {
+		t.Errorf("expected the started response to be left alone, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestLogger_MiddlewareDisableRecovery(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	handler := logger.Middleware(log.MiddlewareOptions{DisableRecovery: true})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("order not loaded")
+	}))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected the panic to propagate")
+		}
+		if got := entries(); len(got) != 0 {
+			t.Errorf("expected nothing logged, got %v", got)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
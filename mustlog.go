@@ -0,0 +1,29 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// mustLogMarker identifies the field returned by MustLog.
+var mustLogMarker = new(struct{})
+
+// MustLog returns a field that exempts its entry from everything that drops
+// entries to limit volume: Sampling, AdaptiveSampling, MaxBytesPerSecond, and
+// RepeatCooldown. The entry is still subject to the level, and is written
+// synchronously even with Async. The field itself adds nothing to the entry.
+//
+// Use it for the few lines that must survive heavy sampling, such as
+// startup, shutdown, or audit entries.
+//
+// Example:
+//
+//	logger.Info("req-123", "payment captured", nil, log.MustLog(), log.String("order_id", orderID))
+func MustLog() Field {
+	return Field{zapField: zap.Field{Type: zapcore.SkipType, Interface: mustLogMarker}}
+}
+
+// isMustLog reports whether f was returned by MustLog.
+func isMustLog(f Field) bool {
+	return f.zapField.Type == zapcore.SkipType && f.zapField.Interface == mustLogMarker
+}
@@ -0,0 +1,52 @@
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func TestMustLog(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{
+		Level:             log.InfoLevel,
+		Sampling:          &log.SamplingConfig{Initial: 1},
+		MaxBytesPerSecond: 300,
+		RepeatCooldown:    time.Minute,
+	})
+	child := logger.With(log.String("layer", "payments"))
+
+	for range 5 {
+		child.Info("req-123", "payment captured", nil, log.MustLog())
+	}
+	for range 5 {
+		child.Info("req-123", "payment retried", nil)
+	}
+
+	var captured, retried int
+	for _, logEntry := range entries() {
+		switch logEntry["message"] {
+		case "payment captured":
+			captured++
+			if logEntry["layer"] != "payments" || logEntry["service"] != "test-service" {
+				t.Errorf("expected bound and default fields on MustLog entries, got %v", logEntry)
+			}
+			if _, exists := logEntry["repeats_suppressed"]; exists {
+				t.Errorf("expected MustLog entries not to report repeats, got %v", logEntry)
+			}
+		case "payment retried":
+			retried++
+		}
+	}
+	if captured != 5 {
+		t.Errorf("expected all 5 MustLog entries, got %d", captured)
+	}
+	if retried != 1 {
+		t.Errorf("expected other entries to be sampled to 1, got %d", retried)
+	}
+
+	logger.Debug("req-123", "below level", nil, log.MustLog())
+	if got := entries(); len(got) != 0 {
+		t.Errorf("expected MustLog entries below the level to be filtered, got %v", got)
+	}
+}
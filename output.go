@@ -1,5 +1,16 @@
 package log
 
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/glennprays/log/internal/zapimpl"
+)
+
 // OutputType specifies the destination for log output.
 type OutputType string
 
@@ -9,12 +20,134 @@ const (
 	// Logs are written as JSON, one entry per line.
 	OutputStdout OutputType = "stdout"
 
+	// OutputStderr writes logs to standard error, in the same format as
+	// OutputStdout. Internal errors also go to standard error unless
+	// Config.InternalErrorWriter is set.
+	OutputStderr OutputType = "stderr"
+
 	// OutputFile writes logs to a file with automatic rotation.
 	// Rotation is handled by lumberjack based on MaxSizeMB, MaxBackups, and MaxAgeDays settings.
 	OutputFile OutputType = "file"
+
+	// OutputChannel sends each encoded entry to a caller-provided channel.
+	// Use NewChannelLogger or set Config.Channel to supply the channel.
+	OutputChannel OutputType = "channel"
 )
 
 // String returns the string representation of the OutputType.
 func (o OutputType) String() string {
 	return string(o)
 }
+
+// WriteSyncer is a destination for encoded entries. Each Write call receives
+// one complete entry; Sync flushes any buffered data. Any zapcore.WriteSyncer
+// satisfies this interface.
+type WriteSyncer interface {
+	io.Writer
+	Sync() error
+}
+
+// OutputFactory creates the WriteSyncer for a registered output from the
+// validated logger configuration.
+type OutputFactory func(cfg Config) (WriteSyncer, error)
+
+var (
+	outputsMu sync.RWMutex
+	outputs   = map[OutputType]OutputFactory{}
+)
+
+// RegisterOutput makes a custom output available under name, so it can be
+// selected by setting Config.Output to OutputType(name). New calls factory
+// once per logger; if the returned WriteSyncer also implements io.Closer,
+// Logger.Close closes it.
+//
+// RegisterOutput is intended to be called from init functions. It panics if
+// name is empty, is a built-in output, or is already registered, or if factory
+// is nil.
+//
+// Example:
+//
+//	func init() {
+//	    log.RegisterOutput("kafka", func(cfg log.Config) (log.WriteSyncer, error) {
+//	        return newKafkaWriter(cfg.Service)
+//	    })
+//	}
+//
+//	logger, err := log.New(log.Config{
+//	    // ...
+//	    Output: "kafka",
+//	})
+func RegisterOutput(name string, factory OutputFactory) {
+	if name == "" {
+		panic("log: output name cannot be empty")
+	}
+	if factory == nil {
+		panic("log: output factory cannot be nil")
+	}
+
+	output := OutputType(name)
+	switch output {
+	case OutputStdout, OutputStderr, OutputFile, OutputChannel:
+		panic(fmt.Sprintf("log: cannot register built-in output %q", name))
+	}
+
+	outputsMu.Lock()
+	defer outputsMu.Unlock()
+	if _, exists := outputs[output]; exists {
+		panic(fmt.Sprintf("log: output %q is already registered", name))
+	}
+	outputs[output] = factory
+}
+
+// registeredOutput returns the factory registered for output, if any.
+func registeredOutput(output OutputType) (OutputFactory, bool) {
+	outputsMu.RLock()
+	defer outputsMu.RUnlock()
+	factory, ok := outputs[output]
+	return factory, ok
+}
+
+// checkFileOutput reports whether path can be opened for appending, creating
+// it and its directory the way the rotating file output does. The file output
+// itself opens path on the first write, too late to fall back.
+func checkFileOutput(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// fallBackToStdout reports that cfg's output failed with err and returns cfg
+// changed to write to stdout, see Config.FallbackToStdout.
+func fallBackToStdout(cfg Config, err error) Config {
+	fmt.Fprintf(internalErrorWriter(cfg), "log: failed to open output %q, falling back to stdout: %v\n", cfg.Output, err)
+	cfg.Output = OutputStdout
+	cfg.FilePath = ""
+	cfg.Checksum = false
+	return cfg
+}
+
+// internalErrorWriter returns where cfg's diagnostics are written.
+func internalErrorWriter(cfg Config) io.Writer {
+	if cfg.InternalErrorWriter == nil {
+		return os.Stderr
+	}
+	return cfg.InternalErrorWriter
+}
+
+// tieredFiles returns the cold tier settings for cfg, or nil without ColdDir.
+func tieredFiles(cfg Config) *zapimpl.TieredFiles {
+	if cfg.ColdDir == "" {
+		return nil
+	}
+	return &zapimpl.TieredFiles{
+		ColdDir: cfg.ColdDir,
+		HotAge:  time.Duration(cfg.HotAgeMinutes) * time.Minute,
+		MaxHot:  cfg.MaxBackups,
+		MaxAge:  time.Duration(cfg.MaxAgeDays) * 24 * time.Hour,
+	}
+}
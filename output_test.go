@@ -0,0 +1,460 @@
+package log_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+// memoryOutput is a WriteSyncer that records entries and whether it was closed.
+type memoryOutput struct {
+	mu      sync.Mutex
+	entries [][]byte
+	closed  bool
+}
+
+func (m *memoryOutput) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, bytes.Clone(p))
+	return len(p), nil
+}
+
+func (m *memoryOutput) Sync() error { return nil }
+
+func (m *memoryOutput) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
+
+func TestRegisterOutput(t *testing.T) {
+	out := &memoryOutput{}
+	var gotService string
+	log.RegisterOutput("test-memory", func(cfg log.Config) (log.WriteSyncer, error) {
+		gotService = cfg.Service
+		return out, nil
+	})
+
+	logger, err := log.New(log.Config{
+		Service: "test-service",
+		Env:     "dev",
+		Level:   log.InfoLevel,
+		Output:  "test-memory",
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	if gotService != "test-service" {
+		t.Errorf("expected factory to receive config, got service %q", gotService)
+	}
+
+	logger.Info("req-123", "custom output", nil)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if len(out.entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(out.entries))
+	}
+	var logEntry map[string]any
+	if err := json.Unmarshal(out.entries[0], &logEntry); err != nil {
+		t.Fatalf("entry is not valid JSON: %v", err)
+	}
+	if logEntry["message"] != "custom output" {
+		t.Errorf("expected message=custom output, got %v", logEntry["message"])
+	}
+	if !out.closed {
+		t.Error("expected Close to close the custom output")
+	}
+}
+
+func TestRegisterOutput_FactoryError(t *testing.T) {
+	log.RegisterOutput("test-failing", func(cfg log.Config) (log.WriteSyncer, error) {
+		return nil, errors.New("broker unreachable")
+	})
+
+	_, err := log.New(log.Config{
+		Service: "test-service",
+		Env:     "dev",
+		Level:   log.InfoLevel,
+		Output:  "test-failing",
+	})
+	if err == nil || !strings.Contains(err.Error(), "broker unreachable") {
+		t.Errorf("expected factory error, got %v", err)
+	}
+}
+
+func TestNew_Stderr(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	defer reader.Close()
+	stderr := os.Stderr
+	os.Stderr = writer
+	defer func() { os.Stderr = stderr }()
+
+	logger, err := log.New(log.Config{
+		Service: "test-service",
+		Env:     "dev",
+		Level:   log.InfoLevel,
+		Output:  log.OutputStderr,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.Info("req-123", "to stderr", nil)
+	writer.Close()
+	out, _ := io.ReadAll(reader)
+	if !strings.Contains(string(out), `"message":"to stderr"`) {
+		t.Errorf("expected entry on stderr, got %q", out)
+	}
+}
+
+func TestNew_FallbackToStdout(t *testing.T) {
+	log.RegisterOutput("test-fallback", func(cfg log.Config) (log.WriteSyncer, error) {
+		return nil, errors.New("broker unreachable")
+	})
+
+	tests := []struct {
+		name     string
+		output   log.OutputType
+		filePath string
+		want     string
+	}{
+		{"file", log.OutputFile, "output_test.go/unwritable.log", `failed to open output "file"`},
+		{"registered output", "test-fallback", "", "broker unreachable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, writer, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("failed to create pipe: %v", err)
+			}
+			defer reader.Close()
+			stdout := os.Stdout
+			os.Stdout = writer
+			defer func() { os.Stdout = stdout }()
+
+			var diagnostics bytes.Buffer
+			logger, err := log.New(log.Config{
+				Service:             "test-service",
+				Env:                 "dev",
+				Level:               log.InfoLevel,
+				Output:              tt.output,
+				FilePath:            tt.filePath,
+				FallbackToStdout:    true,
+				InternalErrorWriter: &diagnostics,
+			})
+			if err != nil {
+				t.Fatalf("expected fallback instead of error, got %v", err)
+			}
+			if !strings.Contains(diagnostics.String(), tt.want) || !strings.Contains(diagnostics.String(), "falling back to stdout") {
+				t.Errorf("expected fallback warning containing %q, got %q", tt.want, diagnostics.String())
+			}
+
+			logger.Info("req-123", "still logged", nil)
+			writer.Close()
+			out, _ := io.ReadAll(reader)
+			if !strings.Contains(string(out), `"message":"still logged"`) {
+				t.Errorf("expected entry on stdout, got %q", out)
+			}
+		})
+	}
+}
+
+func TestNew_FallbackToStdout_WritableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	var diagnostics bytes.Buffer
+	logger, err := log.New(log.Config{
+		Service:             "test-service",
+		Env:                 "dev",
+		Level:               log.InfoLevel,
+		Output:              log.OutputFile,
+		FilePath:            path,
+		FallbackToStdout:    true,
+		InternalErrorWriter: &diagnostics,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("req-123", "to file", nil)
+	logger.Close()
+
+	if diagnostics.Len() != 0 {
+		t.Errorf("expected no fallback for a writable file, got %q", diagnostics.String())
+	}
+	if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), "to file") {
+		t.Errorf("expected entry in %s, got %q (%v)", path, data, err)
+	}
+}
+
+func TestRegisterOutput_Panics(t *testing.T) {
+	factory := func(cfg log.Config) (log.WriteSyncer, error) { return &memoryOutput{}, nil }
+	log.RegisterOutput("test-duplicate", factory)
+
+	tests := []struct {
+		name    string
+		output  string
+		factory log.OutputFactory
+	}{
+		{name: "empty name", output: "", factory: factory},
+		{name: "nil factory", output: "test-nil", factory: nil},
+		{name: "built-in output", output: "stdout", factory: factory},
+		{name: "duplicate", output: "test-duplicate", factory: factory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic, got none")
+				}
+			}()
+			log.RegisterOutput(tt.output, tt.factory)
+		})
+	}
+}
+
+func TestNew_UnregisteredOutput(t *testing.T) {
+	_, err := log.New(log.Config{
+		Service: "test-service",
+		Env:     "dev",
+		Level:   log.InfoLevel,
+		Output:  "not-registered",
+	})
+	if err == nil {
+		t.Error("expected error for unregistered output, got nil")
+	}
+}
+
+func TestConfig_MaxBytesPerSecond(t *testing.T) {
+	ch := make(chan []byte, 100)
+	logger, err := log.NewChannelLogger(log.Config{
+		Service:           "test-service",
+		Env:               "dev",
+		Level:             log.InfoLevel,
+		MaxBytesPerSecond: 1000,
+	}, ch)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	for range 50 {
+		logger.Info("req-123", "flood", nil, log.String("padding", strings.Repeat("x", 100)))
+	}
+
+	written := len(ch)
+	if written == 0 || written >= 50 {
+		t.Fatalf("expected the budget to allow some but not all entries, got %d of 50", written)
+	}
+	stats := logger.Stats()
+	if stats.RateLimitedEntries != uint64(50-written) || stats.RateLimitedBytes == 0 {
+		t.Errorf("expected %d rate-limited entries with their bytes, got %+v", 50-written, stats)
+	}
+
+	// Closing reports the drops, bypassing the exhausted budget
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if len(ch) != written+1 {
+		t.Fatalf("expected a summary entry on Close, got %d new entries", len(ch)-written)
+	}
+	for range written {
+		<-ch
+	}
+	var summary map[string]any
+	if err := json.Unmarshal(<-ch, &summary); err != nil {
+		t.Fatalf("summary is not valid JSON: %v", err)
+	}
+	if summary["level"] != "warn" || summary["dropped_entries"] != float64(stats.RateLimitedEntries) ||
+		summary["dropped_bytes"] != float64(stats.RateLimitedBytes) || summary["max_bytes_per_second"] != float64(1000) {
+		t.Errorf("unexpected summary %v", summary)
+	}
+}
+
+func TestConfig_ColdDir(t *testing.T) {
+	// Near the real time, since lumberjack deletes backups past MaxAgeDays itself
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	dir := t.TempDir()
+	hotDir := filepath.Join(dir, "hot")
+	coldDir := filepath.Join(dir, "cold")
+	if err := os.MkdirAll(coldDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotated files use lumberjack's naming: app-<UTC timestamp>.log
+	rotated := func(age time.Duration) string {
+		return "app-" + now.Add(-age).Format("2006-01-02T15-04-05.000") + ".log"
+	}
+	old, recent, expired := rotated(2*time.Hour), rotated(10*time.Minute), rotated(40*24*time.Hour)
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(hotDir, old), "old entries\n")
+	write(filepath.Join(hotDir, recent), "recent entries\n")
+	write(filepath.Join(hotDir, "other.log"), "not rotated\n")
+	write(filepath.Join(coldDir, expired+".gz"), "expired")
+
+	var diagnostics bytes.Buffer
+	logger, err := log.New(log.Config{
+		Service:             "test-service",
+		Env:                 "dev",
+		Level:               log.InfoLevel,
+		Output:              log.OutputFile,
+		FilePath:            filepath.Join(hotDir, "app.log"),
+		ColdDir:             coldDir,
+		HotAgeMinutes:       60,
+		Clock:               fixedClock{now: now},
+		InternalErrorWriter: &diagnostics,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("req-123", "active file stays hot", nil)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("failed to close logger: %v", err)
+	}
+	if diagnostics.Len() != 0 {
+		t.Errorf("unexpected diagnostics: %q", diagnostics.String())
+	}
+
+	for _, name := range []string{"app.log", recent, "other.log"} {
+		if _, err := os.Stat(filepath.Join(hotDir, name)); err != nil {
+			t.Errorf("expected %s to stay hot: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(hotDir, old)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected %s to leave the hot directory, got %v", old, err)
+	}
+	if _, err := os.Stat(filepath.Join(coldDir, expired+".gz")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected cold file older than MaxAgeDays to be deleted, got %v", err)
+	}
+
+	file, err := os.Open(filepath.Join(coldDir, old+".gz"))
+	if err != nil {
+		t.Fatalf("expected compressed cold file: %v", err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("cold file is not gzip: %v", err)
+	}
+	if data, _ := io.ReadAll(gz); string(data) != "old entries\n" {
+		t.Errorf("expected original content in cold file, got %q", data)
+	}
+}
+
+func TestConfig_ColdDirMaxBackups(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	dir := t.TempDir()
+	coldDir := filepath.Join(dir, "cold")
+
+	var names []string
+	for _, age := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		name := "app-" + now.Add(-age).Format("2006-01-02T15-04-05.000") + ".log"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("entries\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+
+	logger, err := log.New(log.Config{
+		Service:       "test-service",
+		Env:           "dev",
+		Level:         log.InfoLevel,
+		Output:        log.OutputFile,
+		FilePath:      filepath.Join(dir, "app.log"),
+		MaxBackups:    1,
+		ColdDir:       coldDir,
+		HotAgeMinutes: 60,
+		Clock:         fixedClock{now: now},
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("failed to close logger: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, names[0])); err != nil {
+		t.Errorf("expected the newest backup to stay hot: %v", err)
+	}
+	for _, name := range names[1:] {
+		if _, err := os.Stat(filepath.Join(coldDir, name+".gz")); err != nil {
+			t.Errorf("expected %s beyond MaxBackups to move cold rather than be deleted: %v", name, err)
+		}
+	}
+}
+
+func TestConfig_EphemeralFile(t *testing.T) {
+	newLogger := func(t *testing.T) (*log.Logger, string) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "debug.log")
+		logger, err := log.New(log.Config{
+			Service:       "test-service",
+			Env:           "dev",
+			Level:         log.InfoLevel,
+			Output:        log.OutputFile,
+			FilePath:      path,
+			EphemeralFile: true,
+		})
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		return logger, path
+	}
+
+	t.Run("clean close", func(t *testing.T) {
+		logger, path := newLogger(t)
+		backup := filepath.Join(filepath.Dir(path), "debug-2025-01-15T10-30-00.000.log")
+		if err := os.WriteFile(backup, []byte("rotated\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		logger.Info("req-123", "debugging", nil)
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected the file while the logger is open: %v", err)
+		}
+		if err := logger.Close(); err != nil {
+			t.Fatalf("Close returned error: %v", err)
+		}
+		for _, file := range []string{path, backup} {
+			if _, err := os.Stat(file); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("expected %s to be removed, got %v", file, err)
+			}
+		}
+	})
+
+	t.Run("fatal", func(t *testing.T) {
+		defer log.SetExit(func(int) {})()
+		logger, path := newLogger(t)
+
+		logger.Fatal("req-123", "crashed", nil)
+		if err := logger.Close(); err != nil {
+			t.Fatalf("Close returned error: %v", err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected the file to be kept after a fatal entry: %v", err)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package log
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// rateLimitSummaryInterval is how often dropped entries are summarized.
+const rateLimitSummaryInterval = 10 * time.Second
+
+// rateLimitTraceID is the traceId of rate limit summaries.
+const rateLimitTraceID = "log-rate-limit"
+
+// startRateLimitSummaries logs, every rateLimitSummaryInterval and when the
+// logger is closed, a warning with the entries and bytes dropped by
+// Config.MaxBytesPerSecond since the previous summary. Summaries bypass the
+// limit so they are never dropped themselves.
+func (l *Logger) startRateLimitSummaries(maxBytesPerSecond int) {
+	summary := *l
+	summary.zapLogger = l.pipeline.Unlimited
+
+	var reportedEntries, reportedBytes uint64
+	report := func() {
+		entries, bytes := l.stats.RateLimitedEntries.Load(), l.stats.RateLimitedBytes.Load()
+		if entries == reportedEntries {
+			return
+		}
+		summary.log(zapcore.WarnLevel, 1, time.Time{}, rateLimitTraceID, "log output rate limited", nil, []Field{
+			Uint64("dropped_entries", entries-reportedEntries),
+			Uint64("dropped_bytes", bytes-reportedBytes),
+			Int("max_bytes_per_second", maxBytesPerSecond),
+		})
+		reportedEntries, reportedBytes = entries, bytes
+	}
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+
+		ticker := time.NewTicker(rateLimitSummaryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				report() // Drops since the last tick
+				return
+			case <-ticker.C:
+				report()
+			}
+		}
+	}()
+
+	var once sync.Once
+	l.pipeline.OnClose(func() error {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+		return nil
+	})
+}
@@ -0,0 +1,147 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// timestampLayout is the layout of the 'timestamp' field (zap's ISO8601 encoder).
+const timestampLayout = "2006-01-02T15:04:05.000Z0700"
+
+// Entry is a log entry parsed by EntryReader.
+type Entry struct {
+	Timestamp time.Time
+	Level     Level
+	Message   string
+	Service   string
+	Env       string
+	TraceID   string
+
+	// Metadata is the decoded 'metadata' value: nil, a map[string]any, a
+	// []any, or a scalar. Numbers are json.Number.
+	Metadata any
+
+	// Fields holds every other field, decoded the same way as Metadata.
+	Fields map[string]any
+}
+
+// EntryReader reads entries written by a JSON Logger, one per line, for tools
+// that post-process log files.
+type EntryReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NewReader returns an EntryReader that reads newline-delimited JSON entries from r.
+//
+// Example:
+//
+//	reader := log.NewReader(file)
+//	for {
+//	    entry, err := reader.Next()
+//	    if err == io.EOF {
+//	        break
+//	    }
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(entry.Timestamp, entry.TraceID, entry.Message)
+//	}
+func NewReader(r io.Reader) *EntryReader {
+	return &EntryReader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next entry. It returns io.EOF when there are no more
+// entries. Blank lines are skipped. A line that is not a valid entry returns
+// an error naming the line number; the following call continues with the next line.
+func (er *EntryReader) Next() (Entry, error) {
+	for {
+		line, err := er.r.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return Entry{}, err
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return Entry{}, err
+		}
+		er.line++
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		entry, parseErr := parseEntry(line)
+		if parseErr != nil {
+			return Entry{}, fmt.Errorf("log: line %d: %w", er.line, parseErr)
+		}
+		return entry, nil
+	}
+}
+
+// parseEntry decodes one JSON entry, moving the standard fields out of Fields.
+func parseEntry(line []byte) (Entry, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber() // Keep large integers exact
+
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		return Entry{}, err
+	}
+	if fields == nil {
+		return Entry{}, errors.New("entry is not a JSON object")
+	}
+
+	entry := Entry{Fields: fields}
+	if ts, ok := takeString(fields, "timestamp"); ok {
+		t, err := time.Parse(timestampLayout, ts)
+		if err != nil {
+			if t, err = time.Parse(time.RFC3339Nano, ts); err != nil {
+				return Entry{}, fmt.Errorf("invalid timestamp %q", ts)
+			}
+		}
+		entry.Timestamp = t
+	}
+	if level, ok := takeString(fields, "level"); ok {
+		entry.Level = Level(level)
+	} else if status, ok := takeString(fields, "status"); ok {
+		entry.Level = levelFromDatadogStatus(status)
+	}
+	entry.Message, _ = takeString(fields, "message")
+	entry.Service, _ = takeString(fields, "service")
+	if env, ok := takeString(fields, "env"); ok {
+		entry.Env = env
+	} else {
+		entry.Env, _ = takeString(fields, "dd.env")
+	}
+	entry.TraceID, _ = takeString(fields, "trace_id")
+	entry.Metadata = fields["metadata"]
+	delete(fields, "metadata")
+
+	return entry, nil
+}
+
+// takeString removes key from fields and returns its value if it is a string.
+func takeString(fields map[string]any, key string) (string, bool) {
+	value, ok := fields[key].(string)
+	if ok {
+		delete(fields, key)
+	}
+	return value, ok
+}
+
+// levelFromDatadogStatus converts a status written by SchemaDatadog to a Level.
+func levelFromDatadogStatus(status string) Level {
+	switch status {
+	case "warning":
+		return WarnLevel
+	case "critical":
+		return FatalLevel
+	default:
+		return Level(status)
+	}
+}
@@ -0,0 +1,86 @@
+package log_test
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func TestReader_ReadsLoggerOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := log.New(log.Config{
+		Service:  "test-service",
+		Env:      "dev",
+		Level:    log.InfoLevel,
+		Output:   log.OutputFile,
+		FilePath: path,
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	at := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	logger.InfoAt(at, "req-123", "order created", map[string]any{"items": 2}, log.Int64("order_id", 9007199254740993))
+	logger.Warn("req-456", "slow", nil)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open log file: %v", err)
+	}
+	defer file.Close()
+	reader := log.NewReader(file)
+
+	entry, err := reader.Next()
+	if err != nil {
+		t.Fatalf("Next returned error: %v", err)
+	}
+	if !entry.Timestamp.Equal(at) || entry.Level != log.InfoLevel || entry.Message != "order created" ||
+		entry.Service != "test-service" || entry.Env != "dev" || entry.TraceID != "req-123" {
+		t.Errorf("unexpected standard fields: %+v", entry)
+	}
+	if metadata, ok := entry.Metadata.(map[string]any); !ok || metadata["items"] != json.Number("2") {
+		t.Errorf("expected metadata={items: 2}, got %v", entry.Metadata)
+	}
+	if entry.Fields["order_id"] != json.Number("9007199254740993") {
+		t.Errorf("expected exact order_id, got %v", entry.Fields["order_id"])
+	}
+	if _, exists := entry.Fields["trace_id"]; exists {
+		t.Error("expected standard fields to be removed from Fields")
+	}
+
+	entry, err = reader.Next()
+	if err != nil || entry.Level != log.WarnLevel || entry.TraceID != "req-456" {
+		t.Errorf("expected second entry, got %+v (err: %v)", entry, err)
+	}
+
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestReader_InvalidLine(t *testing.T) {
+	input := `{"level":"info","message":"first","trace_id":"a"}` + "\n\nnot json\n" + `{"level":"info","message":"last","trace_id":"b"}`
+	reader := log.NewReader(strings.NewReader(input))
+
+	if entry, err := reader.Next(); err != nil || entry.Message != "first" {
+		t.Fatalf("expected first entry, got %+v (err: %v)", entry, err)
+	}
+	if _, err := reader.Next(); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("expected error naming line 3, got %v", err)
+	}
+	if entry, err := reader.Next(); err != nil || entry.Message != "last" {
+		t.Errorf("expected reading to continue after invalid line, got %+v (err: %v)", entry, err)
+	}
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
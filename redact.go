@@ -0,0 +1,260 @@
+package log
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// redactedValue replaces the value of struct fields tagged log:"redact".
+const redactedValue = "[REDACTED]"
+
+// taggedTypes caches, per struct type, whether it or a struct it contains
+// has log tags, so untagged metadata skips redaction entirely.
+var taggedTypes sync.Map // reflect.Type -> bool
+
+// redactMetadata applies log struct tags to struct metadata:
+//
+//	Password string `log:"-"`      // field omitted
+//	Token    string `log:"redact"` // value replaced with "[REDACTED]"
+//
+// Tags are honored on the metadata struct and on struct fields nested in it,
+// directly or through pointers, slices, arrays, and maps, and on the elements
+// of slice, array, and map metadata. Tagged structs are converted to maps
+// keyed by their json names; values without log tags are returned unchanged. Tagged metadata that refers to itself, which JSON
+// cannot encode either, is replaced by the unserializable-metadata
+// placeholder.
+func redactMetadata(metadata any) any {
+	if metadata == nil {
+		return nil
+	}
+	v := reflect.ValueOf(metadata)
+	if !hasLogTags(v.Type()) {
+		return metadata
+	}
+	r := redactor{visiting: make(map[visitKey]bool)}
+	out, ok := r.value(v)
+	if !ok {
+		return unserializableMetadata(metadata)
+	}
+	return out
+}
+
+// redactor applies log tags to one metadata value.
+type redactor struct {
+	// visiting holds the pointers, maps, and slices on the path to the value
+	// being redacted, to detect cycles.
+	visiting map[visitKey]bool
+}
+
+// visitKey identifies a pointer, map, or slice in redactor.visiting, like
+// encoding/json does.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// value applies log tags to v: structs become maps, and slices, arrays, and
+// maps have their elements redacted. It returns false if v refers to itself.
+func (r *redactor) value(v reflect.Value) (any, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, true
+		}
+		if !r.enter(v) {
+			return nil, false
+		}
+		defer r.leave(v)
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			if v.IsNil() {
+				return nil, true
+			}
+			if !r.enter(v) {
+				return nil, false
+			}
+			defer r.leave(v)
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			redacted, ok := r.value(v.Index(i))
+			if !ok {
+				return nil, false
+			}
+			out[i] = redacted
+		}
+		return out, true
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, true
+		}
+		if !r.enter(v) {
+			return nil, false
+		}
+		defer r.leave(v)
+		// Keep the key type so keys are encoded as encoding/json would
+		out := reflect.MakeMapWithSize(reflect.MapOf(v.Type().Key(), reflect.TypeFor[any]()), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			redacted, ok := r.value(iter.Value())
+			if !ok {
+				return nil, false
+			}
+			elem := reflect.New(reflect.TypeFor[any]()).Elem()
+			if redacted != nil {
+				elem.Set(reflect.ValueOf(redacted))
+			}
+			out.SetMapIndex(iter.Key(), elem)
+		}
+		return out.Interface(), true
+	case reflect.Struct:
+		out := make(map[string]any)
+		if !r.fields(v, out) {
+			return nil, false
+		}
+		return out, true
+	default:
+		return v.Interface(), true
+	}
+}
+
+// enter marks pointer, map, or slice v as being redacted. It returns false
+// if it already is, meaning v refers to itself.
+func (r *redactor) enter(v reflect.Value) bool {
+	key := newVisitKey(v)
+	if r.visiting[key] {
+		return false
+	}
+	r.visiting[key] = true
+	return true
+}
+
+// leave unmarks v once it has been redacted.
+func (r *redactor) leave(v reflect.Value) {
+	delete(r.visiting, newVisitKey(v))
+}
+
+func newVisitKey(v reflect.Value) visitKey {
+	key := visitKey{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+	return key
+}
+
+// fields adds the fields of struct v to out, flattening embedded structs the
+// way encoding/json does. It returns false if v refers to itself.
+func (r *redactor) fields(v reflect.Value, out map[string]any) bool {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := v.Field(i)
+
+		name, omitEmpty, skip := jsonFieldName(sf)
+		if skip || sf.Tag.Get("log") == "-" {
+			continue
+		}
+
+		if sf.Anonymous && name == "" {
+			embedded := fv
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				if !r.enter(embedded) {
+					return false
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				ok := r.fields(embedded, out)
+				if fv.Kind() == reflect.Pointer {
+					r.leave(fv)
+				}
+				if !ok {
+					return false
+				}
+				continue
+			}
+			if fv.Kind() == reflect.Pointer {
+				r.leave(fv)
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		switch {
+		case sf.Tag.Get("log") == "redact":
+			out[name] = redactedValue
+		case hasLogTags(sf.Type):
+			redacted, ok := r.value(fv)
+			if !ok {
+				return false
+			}
+			out[name] = redacted
+		default:
+			out[name] = fv.Interface()
+		}
+	}
+	return true
+}
+
+// jsonFieldName returns the name and omitempty option from a field's json tag,
+// and whether the tag excludes the field.
+func jsonFieldName(sf reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag, ok := sf.Tag.Lookup("json")
+	if !ok {
+		return "", false, false
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(","+opts+",", ",omitempty,"), false
+}
+
+// hasLogTags reports whether t is or contains, through pointers, slices,
+// arrays, and map values, a struct with a log tag on one of its fields or on
+// a struct nested in it.
+func hasLogTags(t reflect.Type) bool {
+	if tagged, ok := taggedTypes.Load(t); ok {
+		return tagged.(bool)
+	}
+	tagged := scanLogTags(t, map[reflect.Type]bool{})
+	taggedTypes.Store(t, tagged)
+	return tagged
+}
+
+// scanLogTags implements hasLogTags, tracking visited types to stop at
+// recursive types.
+func scanLogTags(t reflect.Type, visited map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if _, ok := sf.Tag.Lookup("log"); ok {
+			return true
+		}
+		if scanLogTags(sf.Type, visited) {
+			return true
+		}
+	}
+	return false
+}
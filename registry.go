@@ -0,0 +1,94 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Registry builds and holds named loggers, one per subsystem, so they can be
+// retrieved anywhere and flushed or closed together at shutdown. The zero
+// value is ready to use, and a Registry is safe for concurrent use.
+//
+// Example:
+//
+//	var loggers log.Registry
+//	if err := loggers.Register("billing", billingCfg); err != nil {
+//	    return err
+//	}
+//	defer loggers.CloseAll()
+//
+//	billing, err := loggers.Get("billing")
+type Registry struct {
+	mu      sync.RWMutex
+	loggers map[string]*Logger
+}
+
+// Register builds a logger from cfg with New and stores it under name.
+// It returns an error if name is empty or already registered, or if New fails.
+func (r *Registry) Register(name string, cfg Config) error {
+	if name == "" {
+		return errors.New("logger name cannot be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.loggers[name]; exists {
+		return fmt.Errorf("logger %q is already registered", name)
+	}
+	logger, err := New(cfg)
+	if err != nil {
+		return fmt.Errorf("logger %q: %w", name, err)
+	}
+	if r.loggers == nil {
+		r.loggers = make(map[string]*Logger)
+	}
+	r.loggers[name] = logger
+	return nil
+}
+
+// Get returns the logger registered under name, or an error if there is none.
+func (r *Registry) Get(name string) (*Logger, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	logger, ok := r.loggers[name]
+	if !ok {
+		return nil, fmt.Errorf("logger %q is not registered", name)
+	}
+	return logger, nil
+}
+
+// SyncAll flushes every registered logger, returning the errors of those
+// that failed, each prefixed with the logger's name.
+func (r *Registry) SyncAll() error {
+	return r.each((*Logger).Sync)
+}
+
+// CloseAll closes every registered logger, returning the errors of those
+// that failed, each prefixed with the logger's name. The loggers stay
+// registered; entries logged after CloseAll are handled as after Close.
+func (r *Registry) CloseAll() error {
+	return r.each((*Logger).Close)
+}
+
+// each calls fn on every logger in name order and joins the errors.
+func (r *Registry) each(fn func(*Logger) error) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var errs []error
+	names := make([]string, 0, len(r.loggers))
+	for name := range r.loggers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := fn(r.loggers[name]); err != nil {
+			errs = append(errs, fmt.Errorf("logger %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
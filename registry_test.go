@@ -0,0 +1,62 @@
+package log_test
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func registryConfig(t *testing.T, name string) log.Config {
+	return log.Config{
+		Service:  name,
+		Env:      "dev",
+		Level:    log.InfoLevel,
+		Output:   log.OutputFile,
+		FilePath: filepath.Join(t.TempDir(), name+".log"),
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	var registry log.Registry
+
+	if err := registry.Register("billing", registryConfig(t, "billing")); err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+	if err := registry.Register("search", registryConfig(t, "search")); err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+	if err := registry.Register("billing", registryConfig(t, "billing")); err == nil {
+		t.Error("expected error for a duplicate name, got nil")
+	}
+	if err := registry.Register("broken", log.Config{}); err == nil || !strings.Contains(err.Error(), `"broken"`) {
+		t.Errorf("expected an invalid config error naming the logger, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger, err := registry.Get("billing")
+			if err != nil {
+				t.Errorf("Get returned error: %v", err)
+				return
+			}
+			logger.Info("req-123", "charged", nil)
+		}()
+	}
+	wg.Wait()
+
+	if _, err := registry.Get("missing"); err == nil {
+		t.Error("expected error for an unregistered name, got nil")
+	}
+	if err := registry.SyncAll(); err != nil {
+		t.Errorf("SyncAll returned error: %v", err)
+	}
+	if err := registry.CloseAll(); err != nil {
+		t.Errorf("CloseAll returned error: %v", err)
+	}
+}
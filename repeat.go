@@ -0,0 +1,175 @@
+package log
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// maxRepeatTraces caps how many traces Config.RepeatCooldown tracks at once.
+// Beyond it, entries of new traces are written without suppression.
+const maxRepeatTraces = 10000
+
+// maxRepeatMessages caps how many messages Config.RepeatCooldown tracks per
+// trace. Beyond it, new messages of the trace are written without
+// suppression.
+const maxRepeatMessages = 100
+
+// repeatFilter suppresses an entry when the same trace logged the same
+// message within the cooldown, see Config.RepeatCooldown. It is shared by a
+// logger and its children.
+type repeatFilter struct {
+	cooldown time.Duration
+
+	mu     sync.Mutex
+	traces map[string]map[string]*repeatState // By traceId, then message
+}
+
+// repeatState is when a message was last written for a trace and the
+// repeats of it suppressed since.
+type repeatState struct {
+	level      zapcore.Level
+	written    time.Time
+	suppressed int
+}
+
+// repeatReport is the number of repeats of a message suppressed since it was
+// last written.
+type repeatReport struct {
+	message    string
+	level      zapcore.Level
+	written    time.Time
+	suppressed int
+}
+
+func newRepeatFilter(cooldown time.Duration) *repeatFilter {
+	return &repeatFilter{cooldown: cooldown, traces: make(map[string]map[string]*repeatState)}
+}
+
+// admit reports whether an entry should be written. When it should, it also
+// returns the repeats of the trace's messages suppressed since they were last
+// written, oldest message first, and starts counting them again.
+func (f *repeatFilter) admit(traceId, msg string, level zapcore.Level, now time.Time) (ok bool, reports []repeatReport) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	messages := f.traces[traceId]
+	if messages == nil {
+		if len(f.traces) >= maxRepeatTraces && !f.prune(now) {
+			return true, nil
+		}
+		messages = make(map[string]*repeatState)
+		f.traces[traceId] = messages
+	}
+	state := messages[msg]
+	if state != nil && now.Sub(state.written) < f.cooldown {
+		state.suppressed++
+		return false, nil
+	}
+
+	reports = pendingRepeats(messages)
+	if state == nil {
+		if len(messages) >= maxRepeatMessages && !f.pruneMessages(messages, now) {
+			return true, reports
+		}
+		state = &repeatState{}
+		messages[msg] = state
+	}
+	*state = repeatState{level: level, written: now}
+	return true, reports
+}
+
+// prune forgets messages whose cooldown has passed without suppressed
+// repeats, and traces with none left, and reports whether that made room for
+// another trace.
+func (f *repeatFilter) prune(now time.Time) bool {
+	for traceId, messages := range f.traces {
+		if f.pruneMessages(messages, now); len(messages) == 0 {
+			delete(f.traces, traceId)
+		}
+	}
+	return len(f.traces) < maxRepeatTraces
+}
+
+// pruneMessages forgets the messages of a trace whose cooldown has passed
+// without suppressed repeats, and reports whether that made room for another
+// message.
+func (f *repeatFilter) pruneMessages(messages map[string]*repeatState, now time.Time) bool {
+	for msg, state := range messages {
+		if state.suppressed == 0 && now.Sub(state.written) >= f.cooldown {
+			delete(messages, msg)
+		}
+	}
+	return len(messages) < maxRepeatMessages
+}
+
+// end forgets traceId and returns its pending suppressed repeats, oldest
+// message first.
+func (f *repeatFilter) end(traceId string) []repeatReport {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	reports := pendingRepeats(f.traces[traceId])
+	delete(f.traces, traceId)
+	return reports
+}
+
+// pendingRepeats returns the messages with suppressed repeats, oldest first,
+// and resets their counts.
+func pendingRepeats(messages map[string]*repeatState) []repeatReport {
+	var reports []repeatReport
+	for msg, state := range messages {
+		if state.suppressed > 0 {
+			reports = append(reports, repeatReport{message: msg, level: state.level, written: state.written, suppressed: state.suppressed})
+			state.suppressed = 0
+		}
+	}
+	slices.SortFunc(reports, func(a, b repeatReport) int {
+		return cmp.Or(a.written.Compare(b.written), strings.Compare(a.message, b.message))
+	})
+	return reports
+}
+
+// EndTrace reports the repeats suppressed by Config.RepeatCooldown that no
+// later entry of traceId has reported yet, and stops tracking traceId. Call
+// it when a request ends, so repeats at the end of a request are not lost.
+// The report is written at the level of the suppressed entries:
+//
+//	"message": "repeated entries suppressed", "repeated_message": "retrying", "repeats_suppressed": 41
+//
+// EndTrace does nothing without RepeatCooldown or if no repeats are pending.
+//
+// Example:
+//
+//	func handle(w http.ResponseWriter, r *http.Request) {
+//	    traceID := newTraceID()
+//	    defer logger.EndTrace(traceID)
+//	    // ...
+//	}
+func (l *Logger) EndTrace(traceId string) {
+	if l.repeats == nil {
+		return
+	}
+	traceId = l.resolveTraceID(traceId)
+	l.reportRepeats(1, traceId, l.repeats.end(traceId))
+}
+
+// reportRepeats writes a report entry for each of reports, at the level of
+// the suppressed entries.
+func (l *Logger) reportRepeats(skip int, traceId string, reports []repeatReport) {
+	if len(reports) == 0 {
+		return
+	}
+	report := *l
+	report.repeats = nil // The reports must not start tracking the trace again
+	for _, r := range reports {
+		report.log(r.level, skip+1, time.Time{}, traceId, "repeated entries suppressed", nil, []Field{
+			String("repeated_message", r.message),
+			Int("repeats_suppressed", r.suppressed),
+		})
+	}
+}
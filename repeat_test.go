@@ -0,0 +1,122 @@
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_RepeatCooldown(t *testing.T) {
+	clock := &steppingClock{now: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)}
+	logger, entries := newTestLogger(t, log.Config{Clock: clock, RepeatCooldown: time.Second})
+	child := logger.With(log.String("layer", "db"))
+
+	for range 5 {
+		child.Warn("req-1", "retrying", nil)
+	}
+	logger.Warn("req-2", "retrying", nil) // Other traces are unaffected
+	logger.Info("req-1", "query done", nil)
+
+	got := entries()
+	if len(got) != 3 {
+		t.Fatalf("expected 3 entries, got %d: %v", len(got), got)
+	}
+	if _, exists := got[0]["repeats_suppressed"]; exists {
+		t.Errorf("expected no repeat report on the first entry, got %v", got[0])
+	}
+	if got[1]["trace_id"] != "req-2" {
+		t.Errorf("expected the other trace's entry, got %v", got[1])
+	}
+	if got[2]["message"] != "query done" || got[2]["repeated_message"] != "retrying" || got[2]["repeats_suppressed"] != float64(4) {
+		t.Errorf("expected the next entry to report 4 suppressed repeats, got %v", got[2])
+	}
+	if stats := logger.Stats(); stats.SuppressedRepeats != 4 {
+		t.Errorf("expected 4 suppressed repeats in stats, got %d", stats.SuppressedRepeats)
+	}
+
+	// After the cooldown, the same message is written again
+	logger.Info("req-1", "query done", nil)
+	clock.now = clock.now.Add(time.Second)
+	logger.Info("req-1", "query done", nil)
+	got = entries()
+	if len(got) != 1 || got[0]["repeats_suppressed"] != float64(1) {
+		t.Errorf("expected the repeat after the cooldown to report 1 suppressed entry, got %v", got)
+	}
+
+	restore := log.SetExit(func(int) {})
+	defer restore()
+	logger.Fatal("req-3", "shutting down", nil)
+	logger.Fatal("req-3", "shutting down", nil)
+	if got := entries(); len(got) != 2 {
+		t.Errorf("expected fatal entries never to be suppressed, got %d entries", len(got))
+	}
+}
+
+func TestLogger_EndTrace(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{RepeatCooldown: time.Minute})
+
+	for range 3 {
+		logger.Error("req-1", "cache unavailable", nil)
+	}
+	logger.EndTrace("req-1")
+	logger.EndTrace("req-1") // Already reported
+	logger.EndTrace("req-unknown")
+
+	got := entries()
+	if len(got) != 2 {
+		t.Fatalf("expected the entry and one report, got %d: %v", len(got), got)
+	}
+	report := got[1]
+	if report["level"] != "error" || report["message"] != "repeated entries suppressed" ||
+		report["repeated_message"] != "cache unavailable" || report["repeats_suppressed"] != float64(2) {
+		t.Errorf("unexpected report %v", report)
+	}
+
+	// The trace starts over after EndTrace
+	logger.Error("req-1", "cache unavailable", nil)
+	if got := entries(); len(got) != 1 {
+		t.Errorf("expected the entry to be written after EndTrace, got %v", got)
+	}
+}
+
+func TestLogger_RepeatCooldownInterleaved(t *testing.T) {
+	clock := &steppingClock{now: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC), step: time.Millisecond}
+	logger, entries := newTestLogger(t, log.Config{Clock: clock, RepeatCooldown: time.Second})
+
+	for range 3 {
+		logger.Warn("req-1", "retrying", nil)
+		logger.Info("req-1", "polling", nil)
+	}
+	logger.Info("req-1", "query done", nil)
+
+	got := entries()
+	if len(got) != 4 {
+		t.Fatalf("expected both messages, a report, and the next entry, got %d: %v", len(got), got)
+	}
+	if got[0]["message"] != "retrying" || got[1]["message"] != "polling" {
+		t.Errorf("expected the first of each message to be written, got %v", got[:2])
+	}
+	if report := got[2]; report["level"] != "warn" || report["message"] != "repeated entries suppressed" ||
+		report["repeated_message"] != "retrying" || report["repeats_suppressed"] != float64(2) {
+		t.Errorf("expected a report of 2 suppressed retries, got %v", report)
+	}
+	if got[3]["message"] != "query done" || got[3]["repeated_message"] != "polling" || got[3]["repeats_suppressed"] != float64(2) {
+		t.Errorf("expected the next entry to report 2 suppressed polls, got %v", got[3])
+	}
+
+	for range 2 {
+		logger.Warn("req-1", "retrying", nil)
+		logger.Info("req-1", "polling", nil)
+	}
+	logger.EndTrace("req-1")
+	got = entries()
+	if len(got) != 2 {
+		t.Fatalf("expected one report per message, got %d: %v", len(got), got)
+	}
+	for i, want := range []string{"retrying", "polling"} {
+		if got[i]["repeated_message"] != want || got[i]["repeats_suppressed"] != float64(2) {
+			t.Errorf("expected a report of 2 suppressed %q entries, got %v", want, got[i])
+		}
+	}
+}
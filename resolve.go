@@ -0,0 +1,183 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ResolvedConfig describes the logger New would build from a Config.
+type ResolvedConfig struct {
+	// Config is the validated configuration with all defaults applied.
+	Config Config
+
+	// RequestedLevel is the Level as given. It differs from Config.Level when
+	// the level was invalid and LevelFallback was used instead.
+	RequestedLevel Level
+
+	// Encoder describes how entries are encoded, e.g. "json",
+	// "json (sorted fields)", or "cef".
+	Encoder string
+
+	// Output describes where entries are written, e.g. "stdout" or
+	// "file /var/log/app.log (rotate at 100 MB, keep 3 backups for 28 days)".
+	Output string
+
+	// Fields lists the keys present in every entry, in addition to the
+	// fields passed to each log call. Caller fields are listed only when
+	// captured at every level.
+	Fields []string
+}
+
+// LevelFellBack reports whether LevelFallback replaced an invalid Level.
+func (r ResolvedConfig) LevelFellBack() bool {
+	return r.Config.Level != r.RequestedLevel
+}
+
+// Resolve validates cfg, applies defaults, and describes the logger New would
+// build, without opening files or calling output factories. Use it to lint
+// configuration before deploying.
+//
+// Example:
+//
+//	resolved, err := log.Resolve(cfg)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Printf("level=%s encoder=%s output=%s\n", resolved.Config.Level, resolved.Encoder, resolved.Output)
+func Resolve(cfg Config) (ResolvedConfig, error) {
+	requestedLevel := cfg.Level
+	if err := cfg.Validate(); err != nil {
+		return ResolvedConfig{}, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return ResolvedConfig{
+		Config:         cfg,
+		RequestedLevel: requestedLevel,
+		Encoder:        describeEncoder(cfg),
+		Output:         describeOutput(cfg),
+		Fields:         entryFieldKeys(cfg),
+	}, nil
+}
+
+// describeEncoder returns a description of the encoder for a validated cfg.
+func describeEncoder(cfg Config) string {
+	encoder := cfg.Format.String()
+	if cfg.Format == FormatCSV {
+		return fmt.Sprintf("%s (columns: %s)", encoder, strings.Join(cfg.CSVColumns, ", "))
+	}
+	if cfg.Format != FormatJSON {
+		return encoder
+	}
+
+	var ordering []string
+	if cfg.Schema != SchemaDefault {
+		ordering = append(ordering, cfg.Schema.String()+" schema")
+	}
+	if cfg.MessageFirst {
+		ordering = append(ordering, "message first")
+	}
+	if cfg.SortFields {
+		ordering = append(ordering, "sorted fields")
+	}
+	if len(ordering) > 0 {
+		encoder += " (" + strings.Join(ordering, ", ") + ")"
+	}
+	return encoder
+}
+
+// describeOutput returns a description of the outputs for a validated cfg.
+func describeOutput(cfg Config) string {
+	var output string
+	switch cfg.Output {
+	case OutputStdout:
+		output = "stdout"
+	case OutputStderr:
+		output = "stderr"
+	case OutputFile:
+		if cfg.Checksum {
+			output = fmt.Sprintf("file %s (checksummed, no rotation)", cfg.FilePath)
+			break
+		}
+		output = fmt.Sprintf("file %s (rotate at %d MB, keep %d backups for %d days)",
+			cfg.FilePath, cfg.MaxSizeMB, cfg.MaxBackups, cfg.MaxAgeDays)
+		if cfg.ColdDir != "" {
+			output += fmt.Sprintf(", compressed into %s after %d minutes", cfg.ColdDir, cfg.HotAgeMinutes)
+		}
+	case OutputChannel:
+		output = "channel"
+		if cfg.BlockOnFullChannel {
+			output += " (block when full)"
+		} else {
+			output += " (drop when full)"
+		}
+	default:
+		output = fmt.Sprintf("registered output %q", cfg.Output)
+	}
+
+	if cfg.Output == OutputFile && cfg.EphemeralFile {
+		output += ", removed on clean close"
+	}
+	if cfg.BatchWindow > 0 {
+		output += fmt.Sprintf(", batched every %s up to %d entries", cfg.BatchWindow, cfg.MaxBatchEntries)
+	}
+	if cfg.Async {
+		output += fmt.Sprintf(", async with %d workers and a queue of %d", cfg.AsyncWorkers, cfg.AsyncQueueSize)
+	}
+	if cfg.Console {
+		output += ", mirrored to console"
+	}
+	for _, sink := range cfg.Sinks {
+		output += fmt.Sprintf(", plus %s", describeSink(sink))
+	}
+	return output
+}
+
+// describeSink returns a description of a validated sink.
+func describeSink(sink Sink) string {
+	output := string(sink.Output)
+	if sink.Output == OutputFile {
+		output = "file " + sink.FilePath
+	}
+	if sink.Console {
+		return output + " (console)"
+	}
+	return fmt.Sprintf("%s (%s)", output, sink.Format)
+}
+
+// entryFieldKeys returns the keys present in every entry for a validated cfg.
+func entryFieldKeys(cfg Config) []string {
+	service, env, version := cfg.FieldNames.resolve(cfg.Schema)
+	keys := []string{"timestamp", "level", "message", service, env}
+	if cfg.Schema == SchemaDatadog {
+		keys[1] = "status"
+	}
+	if cfg.Version != "" {
+		keys = append(keys, version)
+	}
+	if cfg.SchemaVersion != "" {
+		keys = append(keys, "schema_version")
+	}
+	if cfg.IncludeBuildInfo {
+		if buildCommit() != "" {
+			keys = append(keys, "commit")
+		}
+		keys = append(keys, "go_version")
+	}
+	if !cfg.AllowEmptyTraceID {
+		keys = append(keys, "trace_id")
+	}
+	keys = append(keys, "metadata")
+	if cfg.IncludeUptime {
+		keys = append(keys, "uptime_ms")
+	}
+	if cfg.IncludeGoroutineID {
+		keys = append(keys, "goroutine_id")
+	}
+	callerMinLevel, _ := cfg.CallerMinLevel.toZapLevel() // Validated; "" is all levels
+	if cfg.EnableCaller && (cfg.CallerMinLevel == "" || callerMinLevel == zapcore.DebugLevel) {
+		keys = append(keys, "caller", "function")
+	}
+	return keys
+}
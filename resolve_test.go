@@ -0,0 +1,57 @@
+package log_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestResolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	resolved, err := log.Resolve(log.Config{
+		Service:       "payments",
+		Env:           "production",
+		Level:         "verbose",
+		LevelFallback: log.InfoLevel,
+		Output:        log.OutputFile,
+		FilePath:      path,
+		SortFields:    true,
+		Async:         true,
+		SchemaVersion: "2",
+		EnableCaller:  true,
+	})
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+
+	if resolved.Config.Level != log.InfoLevel || !resolved.LevelFellBack() || resolved.RequestedLevel != "verbose" {
+		t.Errorf("expected level fallback from verbose to info, got %s (requested %s)", resolved.Config.Level, resolved.RequestedLevel)
+	}
+	if resolved.Config.MaxSizeMB != 100 || resolved.Config.AsyncWorkers != 2 {
+		t.Errorf("expected defaults to be applied, got MaxSizeMB=%d AsyncWorkers=%d", resolved.Config.MaxSizeMB, resolved.Config.AsyncWorkers)
+	}
+	if resolved.Encoder != "json (sorted fields)" {
+		t.Errorf("unexpected encoder %q", resolved.Encoder)
+	}
+	wantOutput := "file " + path + " (rotate at 100 MB, keep 3 backups for 28 days), async with 2 workers and a queue of 1024"
+	if resolved.Output != wantOutput {
+		t.Errorf("unexpected output:\n got: %s\nwant: %s", resolved.Output, wantOutput)
+	}
+	wantFields := []string{"timestamp", "level", "message", "service", "env", "schema_version", "trace_id", "metadata", "caller", "function"}
+	if !slices.Equal(resolved.Fields, wantFields) {
+		t.Errorf("unexpected fields:\n got: %v\nwant: %v", resolved.Fields, wantFields)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected Resolve not to create the log file, got %v", err)
+	}
+}
+
+func TestResolve_InvalidConfig(t *testing.T) {
+	if _, err := log.Resolve(log.Config{Service: "payments"}); err == nil {
+		t.Error("expected error for invalid config, got nil")
+	}
+}
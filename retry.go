@@ -0,0 +1,100 @@
+package log
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// attempt emits the retry counters inline at the top level of the entry.
+type attempt struct {
+	n   int
+	max int
+}
+
+func (a attempt) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("attempt", a.n)
+	if a.max > 0 {
+		enc.AddInt("max_attempts", a.max)
+	}
+	return nil
+}
+
+// Attempt creates fields describing a retry attempt: 'attempt' (n) and
+// 'max_attempts' (max). max_attempts is omitted when max is zero or negative.
+// Attempts are conventionally counted from 1.
+//
+// Example:
+//
+//	for n := 1; n <= maxAttempts; n++ {
+//	    if err := call(); err != nil {
+//	        logger.Warn(traceId, "call failed", nil, log.Attempt(n, maxAttempts), log.Error(err))
+//	        continue
+//	    }
+//	    break
+//	}
+func Attempt(n, max int) Field {
+	return Field{zapField: zap.Inline(attempt{n: n, max: max})}
+}
+
+// RetryLogger logs the attempts of a single retry loop under one trace ID.
+// Every log call counts as the next attempt and includes an 'attempt' field,
+// starting at 1. A RetryLogger is safe for concurrent use.
+type RetryLogger struct {
+	logger  *Logger
+	traceId string
+	attempt atomic.Int64
+}
+
+// Retry returns a RetryLogger that numbers its entries as successive attempts.
+//
+// Example:
+//
+//	retry := logger.Retry(traceId)
+//	for {
+//	    err := call()
+//	    if err == nil {
+//	        break
+//	    }
+//	    retry.Warn("call failed, retrying", nil, log.Error(err)) // attempt=1, 2, ...
+//	}
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+func (l *Logger) Retry(traceId string) *RetryLogger {
+	return &RetryLogger{logger: l, traceId: l.resolveTraceID(traceId)}
+}
+
+// Debug logs the next attempt at debug level.
+func (r *RetryLogger) Debug(msg string, metadata any, fields ...Field) {
+	r.logger.log(zapcore.DebugLevel, 1, time.Time{}, r.traceId, msg, metadata, r.next(fields))
+}
+
+// Info logs the next attempt at info level.
+func (r *RetryLogger) Info(msg string, metadata any, fields ...Field) {
+	r.logger.log(zapcore.InfoLevel, 1, time.Time{}, r.traceId, msg, metadata, r.next(fields))
+}
+
+// Warn logs the next attempt at warn level.
+func (r *RetryLogger) Warn(msg string, metadata any, fields ...Field) {
+	r.logger.log(zapcore.WarnLevel, 1, time.Time{}, r.traceId, msg, metadata, r.next(fields))
+}
+
+// Error logs the next attempt at error level.
+func (r *RetryLogger) Error(msg string, metadata any, fields ...Field) {
+	r.logger.log(zapcore.ErrorLevel, 1, time.Time{}, r.traceId, msg, metadata, r.next(fields))
+}
+
+// Attempts returns the number of attempts logged so far.
+func (r *RetryLogger) Attempts() int {
+	return int(r.attempt.Load())
+}
+
+// next advances the attempt counter and appends the attempt field, without
+// writing to the caller's array.
+func (r *RetryLogger) next(fields []Field) []Field {
+	n := int(r.attempt.Add(1))
+	return append(slices.Clip(fields), Attempt(n, 0))
+}
@@ -0,0 +1,74 @@
+package log_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestAttempt(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+	logger.Warn("req-123", "call failed", nil, log.Attempt(2, 5))
+	logger.Warn("req-123", "call failed", nil, log.Attempt(3, 0))
+
+	got := entries()
+	if got[0]["attempt"] != float64(2) || got[0]["max_attempts"] != float64(5) {
+		t.Errorf("expected attempt=2 max_attempts=5, got %v %v", got[0]["attempt"], got[0]["max_attempts"])
+	}
+	if _, exists := got[1]["max_attempts"]; exists {
+		t.Error("max_attempts should be omitted when max is zero")
+	}
+}
+
+func TestLogger_Retry(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{EnableCaller: true})
+	retry := logger.Retry("req-123")
+
+	retry.Warn("attempt failed", nil)
+	retry.Warn("attempt failed", nil)
+	retry.Info("attempt succeeded", nil)
+
+	if retry.Attempts() != 3 {
+		t.Errorf("expected 3 attempts, got %d", retry.Attempts())
+	}
+
+	for i, logEntry := range entries() {
+		if logEntry["attempt"] != float64(i+1) {
+			t.Errorf("entry %d: expected attempt=%d, got %v", i, i+1, logEntry["attempt"])
+		}
+		if logEntry["trace_id"] != "req-123" {
+			t.Errorf("entry %d: expected trace_id=req-123, got %v", i, logEntry["trace_id"])
+		}
+		caller, _ := logEntry["caller"].(string)
+		if !strings.Contains(caller, "retry_test.go") {
+			t.Errorf("entry %d: caller should point to retry_test.go, got %s", i, caller)
+		}
+	}
+}
+
+func TestLogger_RetryKeepsCallerFields(t *testing.T) {
+	logger, _ := newTestLogger(t, log.Config{})
+	retry := logger.Retry("req-123")
+
+	fields := make([]log.Field, 1, 2)
+	fields[0] = log.String("endpoint", "/charge")
+	spare := fields[:2]
+	spare[1] = log.String("marker", "untouched")
+
+	retry.Warn("attempt failed", nil, fields...)
+	if got := spare[1]; got.Key() != "marker" {
+		t.Errorf("expected the caller's array to be left alone, got field %q", got.Key())
+	}
+}
+
+func TestLogger_RetryEmptyTraceId(t *testing.T) {
+	logger, _ := newTestLogger(t, log.Config{})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for empty traceId, got none")
+		}
+	}()
+	logger.Retry("")
+}
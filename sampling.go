@@ -0,0 +1,104 @@
+package log
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingConfig limits how many entries with the same level and message are
+// written per second. The first Initial entries in each second are written,
+// then every Thereafter-th entry; the rest are dropped. This caps the cost of
+// hot loops that log the same message over and over.
+type SamplingConfig struct {
+	// Initial is the number of entries per second written before sampling starts.
+	Initial int
+
+	// Thereafter writes every Thereafter-th entry after Initial; 0 drops them all.
+	Thereafter int
+}
+
+// AdaptiveSamplingConfig drops debug and info entries only while the output
+// is struggling, instead of at a fixed rate. Warn and above are always
+// written.
+//
+// Every Interval the logger checks the average write latency since the last
+// check and, with Async, the fraction of the queue in use. If either is above
+// its threshold, it keeps half as many debug and info entries as before, down
+// to one in MaxRatio; otherwise it keeps twice as many, up to all of them.
+// Dropped entries are counted in Stats.AdaptiveDroppedEntries.
+type AdaptiveSamplingConfig struct {
+	// LatencyThreshold is the average write latency above which the output
+	// is considered under pressure. Default: 10ms.
+	LatencyThreshold time.Duration
+
+	// QueueThreshold is the fraction of the async queue in use, from 0 to 1,
+	// above which the output is considered under pressure. Only used when
+	// Async is true. Default: 0.8.
+	QueueThreshold float64
+
+	// Interval is how often the output's health is checked. Default: 1s.
+	Interval time.Duration
+
+	// MaxRatio caps sampling at keeping one debug or info entry in MaxRatio.
+	// Default: 64.
+	MaxRatio int
+}
+
+// samplingSummaryInterval is how often entries dropped by sampling are
+// summarized.
+const samplingSummaryInterval = 10 * time.Second
+
+// samplingTraceID is the traceId of sampling summaries.
+const samplingTraceID = "log-sampling"
+
+// startSamplingSummaries logs, every samplingSummaryInterval and when the
+// logger is closed, how many entries Config.Sampling and
+// Config.AdaptiveSampling dropped since the previous summary. Summaries
+// bypass sampling so they are never dropped themselves.
+func (l *Logger) startSamplingSummaries() {
+	summary := *l
+	summary.zapLogger = l.pipeline.Unlimited
+
+	var reportedSampled, reportedAdaptive uint64
+	report := func() {
+		sampled, adaptive := l.stats.SamplingDroppedEntries.Load(), l.stats.AdaptiveDroppedEntries.Load()
+		if sampled == reportedSampled && adaptive == reportedAdaptive {
+			return
+		}
+		summary.log(zapcore.InfoLevel, 1, time.Time{}, samplingTraceID, "log entries sampled out", nil, []Field{
+			Uint64("dropped_entries", sampled-reportedSampled),
+			Uint64("adaptive_dropped_entries", adaptive-reportedAdaptive),
+		})
+		reportedSampled, reportedAdaptive = sampled, adaptive
+	}
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+
+		ticker := time.NewTicker(samplingSummaryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				report() // Drops since the last tick
+				return
+			case <-ticker.C:
+				report()
+			}
+		}
+	}()
+
+	var once sync.Once
+	l.pipeline.OnClose(func() error {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+		return nil
+	})
+}
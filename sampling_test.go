@@ -0,0 +1,132 @@
+package log_test
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+// slowOutput is a WriteSyncer whose writes take a configurable time.
+type slowOutput struct {
+	delay atomic.Int64 // time.Duration
+	mu    sync.Mutex
+	warns int
+}
+
+func (s *slowOutput) Write(p []byte) (int, error) {
+	time.Sleep(time.Duration(s.delay.Load()))
+	if bytes.Contains(p, []byte(`"level":"warn"`)) {
+		s.mu.Lock()
+		s.warns++
+		s.mu.Unlock()
+	}
+	return len(p), nil
+}
+
+func (s *slowOutput) Sync() error { return nil }
+
+func TestConfig_AdaptiveSampling(t *testing.T) {
+	out := &slowOutput{}
+	out.delay.Store(int64(2 * time.Millisecond))
+	log.RegisterOutput("test-slow", func(log.Config) (log.WriteSyncer, error) {
+		return out, nil
+	})
+
+	logger, err := log.New(log.Config{
+		Service: "test-service",
+		Env:     "dev",
+		Level:   log.InfoLevel,
+		Output:  "test-slow",
+		AdaptiveSampling: &log.AdaptiveSamplingConfig{
+			LatencyThreshold: time.Millisecond,
+			Interval:         10 * time.Millisecond,
+			MaxRatio:         8,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logFor := func(d time.Duration) (warns int) {
+		for deadline := time.Now().Add(d); time.Now().Before(deadline); {
+			logger.Info("req-123", "busy", nil)
+			logger.Warn("req-123", "always kept", nil)
+			warns++
+		}
+		return warns
+	}
+
+	// Slow output: info entries are dropped, warn entries are not
+	warns := logFor(150 * time.Millisecond)
+	dropped := logger.Stats().AdaptiveDroppedEntries
+	if dropped == 0 {
+		t.Fatal("expected info entries to be dropped while the output is slow")
+	}
+	if out.warns != warns {
+		t.Errorf("expected all %d warn entries written, got %d", warns, out.warns)
+	}
+
+	// Healthy output: sampling backs off to writing everything
+	out.delay.Store(0)
+	logFor(100 * time.Millisecond)
+	recovered := logger.Stats().AdaptiveDroppedEntries
+	for range 100 {
+		logger.Info("req-123", "healthy", nil)
+	}
+	if got := logger.Stats().AdaptiveDroppedEntries; got != recovered {
+		t.Errorf("expected no drops once the output recovered, got %d more", got-recovered)
+	}
+}
+
+func TestConfig_InvalidAdaptiveSampling(t *testing.T) {
+	cfg := log.Config{
+		Service:          "test-service",
+		Env:              "dev",
+		Level:            log.InfoLevel,
+		Output:           log.OutputStdout,
+		AdaptiveSampling: &log.AdaptiveSamplingConfig{QueueThreshold: 1.5},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for queue threshold above 1, got nil")
+	}
+}
+
+func TestConfig_IncludeSampleDecision(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{
+		Sampling:              &log.SamplingConfig{Initial: 2},
+		IncludeSampleDecision: true,
+	})
+	for range 5 {
+		logger.Info("req-123", "polling", nil)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	got := entries()
+	if len(got) != 3 {
+		t.Fatalf("expected 2 sampled entries and a summary, got %v", got)
+	}
+	for _, logEntry := range got[:2] {
+		if logEntry["message"] != "polling" || logEntry["sampled"] != true {
+			t.Errorf("expected sampled entry with 'sampled': true, got %v", logEntry)
+		}
+	}
+	summary := got[2]
+	if summary["message"] != "log entries sampled out" || summary["trace_id"] != "log-sampling" || summary["dropped_entries"] != float64(3) {
+		t.Errorf("expected a summary of 3 dropped entries, got %v", summary)
+	}
+	if stats := logger.Stats(); stats.SamplingDroppedEntries != 3 {
+		t.Errorf("expected 3 entries dropped by sampling in stats, got %d", stats.SamplingDroppedEntries)
+	}
+
+	unsampled, entries := newTestLogger(t, log.Config{IncludeSampleDecision: true})
+	unsampled.Info("req-123", "polling", nil)
+	if got := entries(); len(got) != 1 || got[0]["sampled"] != nil {
+		t.Errorf("expected no 'sampled' field without sampling, got %v", got)
+	}
+}
@@ -0,0 +1,84 @@
+package log
+
+import (
+	"context"
+	"slices"
+	"strconv"
+)
+
+// Schema selects the key layout of JSON entries for a specific consumer.
+type Schema string
+
+const (
+	// SchemaDefault is the library's own layout, described in the README.
+	SchemaDefault Schema = ""
+
+	// SchemaDatadog uses Datadog's reserved attributes: the level is written
+	// as 'status' with Datadog's values (debug, info, warning, error,
+	// critical), and 'env' as 'dd.env'. 'timestamp', 'message', and 'service'
+	// are already reserved attributes and keep their names.
+	SchemaDatadog Schema = "datadog"
+)
+
+// String returns the string representation of the Schema.
+func (s Schema) String() string {
+	return string(s)
+}
+
+// datadogTraceKey is the context key for the span stored by
+// ContextWithDatadogTrace.
+type datadogTraceKey struct{}
+
+// datadogSpan holds the IDs of a Datadog APM span.
+type datadogSpan struct {
+	traceID, spanID uint64
+}
+
+// ContextWithDatadogTrace returns a copy of ctx carrying the IDs of the
+// active Datadog span. Loggers with SchemaDatadog add them to entries as
+// 'dd.trace_id' and 'dd.span_id', which Datadog uses to link logs to APM
+// traces, in the logger returned by WithContext and the Ctx methods, such as
+// InfoCtx. Other schemas ignore them.
+//
+// Example:
+//
+//	span, ctx := tracer.StartSpanFromContext(ctx, "checkout")
+//	ctx = log.ContextWithDatadogTrace(ctx, span.Context().TraceID(), span.Context().SpanID())
+//	logger.InfoCtx(ctx, "charged card", nil)
+func ContextWithDatadogTrace(ctx context.Context, traceID, spanID uint64) context.Context {
+	return context.WithValue(ctx, datadogTraceKey{}, datadogSpan{traceID, spanID})
+}
+
+// WithDatadogTrace returns a child logger whose entries include 'dd.trace_id'
+// and 'dd.span_id' with the given IDs, whatever the schema. Prefer
+// ContextWithDatadogTrace, which the Ctx methods pick up; use this where no
+// context is at hand.
+//
+// Example:
+//
+//	spanLogger := logger.WithDatadogTrace(span.Context().TraceID(), span.Context().SpanID())
+func (l *Logger) WithDatadogTrace(traceID, spanID uint64) *Logger {
+	return l.With(datadogSpan{traceID, spanID}.fields()...)
+}
+
+// fields returns the 'dd.trace_id' and 'dd.span_id' fields of the span.
+func (s datadogSpan) fields() []Field {
+	return []Field{
+		String("dd.trace_id", strconv.FormatUint(s.traceID, 10)),
+		String("dd.span_id", strconv.FormatUint(s.spanID, 10)),
+	}
+}
+
+// withDatadogTrace appends the fields of the Datadog span stored in ctx to
+// fields if the logger uses SchemaDatadog, without writing to the caller's
+// array.
+func (l *Logger) withDatadogTrace(ctx context.Context, fields []Field) []Field {
+	if !l.datadogSchema {
+		return fields
+	}
+	span, ok := ctx.Value(datadogTraceKey{}).(datadogSpan)
+	if !ok {
+		return fields
+	}
+	return append(slices.Clip(fields), span.fields()...)
+}
@@ -0,0 +1,95 @@
+package log
+
+import (
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// signalTraceID is the traceId of entries reporting signal-driven level changes.
+const signalTraceID = "log-level-signal"
+
+// EnableSignalLevelControl changes the logger's level when the process
+// receives a signal: up makes logging more verbose (e.g. info to debug) and
+// down less verbose (e.g. info to warn), one level per signal through debug,
+// info, warn, error, panic, and fatal, clamped at the ends. The level is shared with the logger's children. Each change
+// is logged with 'from' and 'to' fields.
+//
+// The returned stop function stops handling the signals and may be called more
+// than once.
+//
+// Example:
+//
+//	stop := logger.EnableSignalLevelControl(syscall.SIGUSR1, syscall.SIGUSR2)
+//	defer stop()
+//
+//	// kill -USR1 <pid>  # more verbose
+//	// kill -USR2 <pid>  # less verbose
+//
+// Panics if up or down is nil, or if they are the same signal.
+func (l *Logger) EnableSignalLevelControl(up, down os.Signal) (stop func()) {
+	if up == nil || down == nil {
+		panic("log: level control signals cannot be nil")
+	}
+	if up == down {
+		panic("log: level control signals must differ")
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, up, down)
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				from := l.level.Level()
+				i := slices.IndexFunc(levels, func(level Level) bool {
+					zapLevel, _ := level.toZapLevel()
+					return zapLevel >= from
+				})
+				if sig == up {
+					i--
+				} else {
+					i++
+				}
+				if i < 0 || i >= len(levels) {
+					continue
+				}
+				to, _ := levels[i].toZapLevel()
+				l.level.SetLevel(to)
+				l.log(levelChangeLevel(to), 1, time.Time{}, signalTraceID, "log level changed", nil, []Field{
+					String("from", from.String()),
+					String("to", to.String()),
+					String("signal", sig.String()),
+				})
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+			<-exited
+		})
+	}
+}
+
+// levelChangeLevel returns the level to report a change to level at: info, or
+// the new level if higher, so the report passes it. A change to panic or fatal
+// is reported at error, which they filter out; logging at those levels would
+// panic or exit.
+func levelChangeLevel(level zapcore.Level) zapcore.Level {
+	return min(max(level, zapcore.InfoLevel), zapcore.ErrorLevel)
+}
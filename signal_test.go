@@ -0,0 +1,105 @@
+//go:build unix
+
+package log_test
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_EnableSignalLevelControl(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{Level: log.InfoLevel})
+	child := logger.With(log.String("layer", "api"))
+
+	stop := logger.EnableSignalLevelControl(syscall.SIGUSR1, syscall.SIGUSR2)
+	defer stop()
+
+	signalAndWait := func(sig syscall.Signal, from, to string) {
+		t.Helper()
+		if err := syscall.Kill(syscall.Getpid(), sig); err != nil {
+			t.Fatalf("failed to send %v: %v", sig, err)
+		}
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			if got := entries(); len(got) > 0 {
+				logEntry := got[0]
+				if logEntry["message"] != "log level changed" || logEntry["from"] != from || logEntry["to"] != to {
+					t.Fatalf("expected change from %s to %s, got %v", from, to, logEntry)
+				}
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		t.Fatalf("no level change logged after %v", sig)
+	}
+
+	child.Debug("req-123", "hidden", nil)
+	signalAndWait(syscall.SIGUSR1, "info", "debug")
+	child.Debug("req-123", "visible", nil)
+	if got := entries(); len(got) != 1 || got[0]["message"] != "visible" {
+		t.Errorf("expected child debug entry after raising verbosity, got %v", got)
+	}
+
+	signalAndWait(syscall.SIGUSR2, "debug", "info")
+	signalAndWait(syscall.SIGUSR2, "info", "warn")
+	logger.Info("req-123", "hidden", nil)
+	if got := entries(); len(got) != 0 {
+		t.Errorf("expected info entries to be filtered at warn, got %v", got)
+	}
+}
+
+func TestLogger_EnableSignalLevelControl_AboveError(t *testing.T) {
+	logger, _ := newTestLogger(t, log.Config{Level: log.ErrorLevel})
+
+	stop := logger.EnableSignalLevelControl(syscall.SIGUSR1, syscall.SIGUSR2)
+	defer stop()
+
+	for _, want := range []log.Level{log.PanicLevel, log.FatalLevel} {
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR2); err != nil {
+			t.Fatalf("failed to send signal: %v", err)
+		}
+		deadline := time.Now().Add(time.Second)
+		for logger.GetLevel() != want && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if got := logger.GetLevel(); got != want {
+			t.Fatalf("expected level %s, got %s", want, got)
+		}
+	}
+
+	for _, want := range []log.Level{log.PanicLevel, log.ErrorLevel} {
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+			t.Fatalf("failed to send signal: %v", err)
+		}
+		deadline := time.Now().Add(time.Second)
+		for logger.GetLevel() != want && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if got := logger.GetLevel(); got != want {
+			t.Fatalf("expected level %s, got %s", want, got)
+		}
+	}
+}
+
+func TestLogger_EnableSignalLevelControl_Clamped(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{Level: log.DebugLevel})
+
+	stop := logger.EnableSignalLevelControl(syscall.SIGUSR1, syscall.SIGUSR2)
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("failed to send signal: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	stop()
+	stop() // Safe to call twice
+
+	if got := entries(); len(got) != 0 {
+		t.Errorf("expected no change below debug, got %v", got)
+	}
+	logger.Debug("req-123", "still debug", nil)
+	if got := entries(); len(got) != 1 {
+		t.Errorf("expected level to stay at debug, got %d entries", len(got))
+	}
+}
@@ -0,0 +1,110 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/glennprays/log/internal/zapimpl"
+)
+
+// Sink is an additional output with its own encoding, for Config.Sinks, such
+// as human-readable lines on stdout next to JSON in a file. Sinks receive
+// every entry the logger's level enables, including sampled entries; the
+// settings that shape the main output's writes, such as Async, BatchWindow,
+// MaxBytesPerSecond, LinePrefix, and SortFields, don't apply to them.
+type Sink struct {
+	// Output is where the sink writes: OutputStdout, OutputStderr,
+	// OutputFile, or a registered output (required). Files are rotated with
+	// Config.MaxSizeMB, MaxBackups, and MaxAgeDays.
+	Output OutputType
+
+	// FilePath is the file to write (required if Output is OutputFile).
+	FilePath string
+
+	// Format is how the sink encodes entries: FormatJSON, FormatCEF,
+	// FormatCSV, or FormatConsole, with the Config's CEF and CSV settings.
+	// Ignored when Console is set.
+	// Default: FormatJSON
+	Format Format
+
+	// Console encodes entries as human-readable lines, like Config.Console;
+	// it is the same as Format FormatConsole. Color colors their levels, for
+	// terminals, and requires either.
+	// Default: false
+	Console bool
+	Color   bool
+
+	// TimeKey renames the timestamp key, e.g. "@timestamp" for an ELK stack.
+	// Default: "timestamp"
+	TimeKey string
+
+	// UppercaseLevels writes levels as "INFO" instead of "info".
+	// Default: false
+	UppercaseLevels bool
+}
+
+// validate checks the sink and sets defaults.
+func (s *Sink) validate() error {
+	var errs []error
+	switch s.Output {
+	case "":
+		errs = append(errs, errors.New("output type is required"))
+	case OutputStdout, OutputStderr:
+	case OutputFile:
+		if strings.TrimSpace(s.FilePath) == "" {
+			errs = append(errs, errors.New("file path is required when output is file"))
+		}
+	case OutputChannel:
+		errs = append(errs, errors.New("output cannot be channel"))
+	default:
+		if _, ok := registeredOutput(s.Output); !ok {
+			errs = append(errs, fmt.Errorf("output must be stdout, stderr, file, or a registered output (got: %s)", s.Output))
+		}
+	}
+
+	if s.Format == "" {
+		s.Format = FormatJSON
+	} else if !slices.Contains([]Format{FormatJSON, FormatCEF, FormatCSV, FormatConsole}, s.Format) {
+		errs = append(errs, fmt.Errorf("format must be json, cef, csv, or console (got: %s)", s.Format))
+	}
+	if s.Color && !s.Console && s.Format != FormatConsole {
+		errs = append(errs, errors.New("color requires console"))
+	}
+	if s.TimeKey != "" && (strings.TrimSpace(s.TimeKey) == "" || (s.TimeKey != "timestamp" && slices.Contains(reservedFieldKeys, s.TimeKey))) {
+		errs = append(errs, fmt.Errorf("time key must not be blank or reserved (got: %q)", s.TimeKey))
+	}
+	return errors.Join(errs...)
+}
+
+// sinkOptions opens the registered outputs of cfg's sinks and returns the
+// sinks for BuildLogger.
+func sinkOptions(cfg Config) ([]zapimpl.SinkOptions, error) {
+	var sinks []zapimpl.SinkOptions
+	for i, sink := range cfg.Sinks {
+		opts := zapimpl.SinkOptions{
+			OutputType:      string(sink.Output),
+			FilePath:        sink.FilePath,
+			Format:          string(sink.Format),
+			Console:         sink.Console || sink.Format == FormatConsole,
+			Color:           sink.Color,
+			TimeKey:         sink.TimeKey,
+			UppercaseLevels: sink.UppercaseLevels,
+		}
+		if factory, ok := registeredOutput(sink.Output); ok {
+			sinkCfg := cfg
+			sinkCfg.Output, sinkCfg.FilePath = sink.Output, sink.FilePath
+			writer, err := factory(sinkCfg)
+			if err == nil && writer == nil {
+				err = errors.New("factory returned nil")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to create output %q of sink %d: %w", sink.Output, i, err)
+			}
+			opts.Writer = writer
+		}
+		sinks = append(sinks, opts)
+	}
+	return sinks, nil
+}
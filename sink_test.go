@@ -0,0 +1,87 @@
+package log_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestConfig_Sinks(t *testing.T) {
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	logger, err := log.New(log.Config{
+		Service:  "test-service",
+		Env:      "dev",
+		Level:    log.InfoLevel,
+		Output:   log.OutputFile,
+		FilePath: path("main.log"),
+		Sinks: []log.Sink{
+			{Output: log.OutputFile, FilePath: path("console.log"), Console: true},
+			{Output: log.OutputFile, FilePath: path("elk.log"), TimeKey: "@timestamp", UppercaseLevels: true},
+			{Output: log.OutputFile, FilePath: path("sheet.csv"), Format: log.FormatCSV},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("req-123", "user logged in", nil, log.String("user_id", "user-456"))
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	read := func(name string) string {
+		t.Helper()
+		content, err := os.ReadFile(path(name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		return string(content)
+	}
+
+	var main map[string]any
+	if err := json.Unmarshal([]byte(read("main.log")), &main); err != nil {
+		t.Fatalf("main output is not JSON: %v", err)
+	}
+	if main["level"] != "info" || main["timestamp"] == nil {
+		t.Errorf("expected the main output unchanged by sinks, got %v", main)
+	}
+
+	if console := read("console.log"); !strings.Contains(console, "\tINFO\tuser logged in\t") || strings.HasPrefix(console, "{") {
+		t.Errorf("expected a console line, got %q", console)
+	}
+
+	var elk map[string]any
+	if err := json.Unmarshal([]byte(read("elk.log")), &elk); err != nil {
+		t.Fatalf("elk output is not JSON: %v", err)
+	}
+	if elk["@timestamp"] == nil || elk["timestamp"] != nil || elk["level"] != "INFO" || elk["user_id"] != "user-456" {
+		t.Errorf("expected the sink's time key and level encoding, got %v", elk)
+	}
+
+	rows := strings.Split(strings.TrimSpace(read("sheet.csv")), "\n")
+	if len(rows) != 2 || !strings.HasPrefix(rows[0], "timestamp,level,message") || !strings.Contains(rows[1], ",info,user logged in,req-123,") {
+		t.Errorf("expected a CSV header and row, got %q", rows)
+	}
+}
+
+func TestConfig_SinksValidation(t *testing.T) {
+	for name, sink := range map[string]log.Sink{
+		"missing output": {},
+		"channel":        {Output: log.OutputChannel},
+		"missing path":   {Output: log.OutputFile},
+		"unknown format": {Output: log.OutputStdout, Format: "xml"},
+		"color":          {Output: log.OutputStdout, Color: true},
+		"reserved time":  {Output: log.OutputStdout, TimeKey: "message"},
+		"unregistered":   {Output: "nowhere"},
+	} {
+		cfg := log.Config{Service: "test-service", Env: "dev", Level: log.InfoLevel, Output: log.OutputStdout, Sinks: []log.Sink{sink}}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
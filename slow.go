@@ -0,0 +1,101 @@
+package log
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TimeThreshold starts timing an operation and returns a function that ends
+// it. The returned function logs msg at warn level with 'duration_ms' and
+// 'threshold_ms' fields only if the operation took longer than threshold, so fast operations stay
+// silent. Time is read from Config.Clock.
+//
+// Example:
+//
+//	func (s *Store) Load(ctx context.Context, traceId string) error {
+//	    defer s.logger.TimeThreshold(traceId, "slow store load", 200*time.Millisecond)()
+//	    // ...
+//	}
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+func (l *Logger) TimeThreshold(traceId string, msg string, threshold time.Duration) func() {
+	traceId = l.resolveTraceID(traceId)
+
+	start := l.clock.Now()
+	return func() {
+		elapsed := l.clock.Now().Sub(start)
+		if elapsed <= threshold {
+			return
+		}
+		l.log(zapcore.WarnLevel, 1, time.Time{}, traceId, msg, nil, []Field{
+			Int64("duration_ms", elapsed.Milliseconds()),
+			Int64("threshold_ms", threshold.Milliseconds()),
+		})
+	}
+}
+
+// maxSlowQueryLength is the length in bytes at which SlowQuery truncates
+// normalized queries.
+const maxSlowQueryLength = 1024
+
+var (
+	// queryLiteral matches quoted string literals and standalone numbers, and
+	// positional parameters such as $1 so they can be kept.
+	queryLiteral = regexp.MustCompile(`\$\d+|'(?:[^']|'')*'|\b\d+(?:\.\d+)?\b`)
+	// queryWhitespace matches runs of whitespace.
+	queryWhitespace = regexp.MustCompile(`\s+`)
+)
+
+// SlowQuery logs a database query at warn level if elapsed is longer than
+// threshold; faster queries are not logged. The entry has the message
+// "slow query", 'log_type' "slow_query", 'duration_ms', 'threshold_ms', and
+// 'query', normalized so that values do not end up in logs: string literals
+// and numbers are replaced with ?, whitespace is collapsed, and the result is
+// truncated to 1024 bytes.
+//
+// Example:
+//
+//	start := time.Now()
+//	rows, err := db.QueryContext(ctx, query, args...)
+//	logger.SlowQuery(traceId, query, time.Since(start), 200*time.Millisecond)
+//	// "query": "SELECT * FROM orders WHERE user_id = ? AND status = ?"
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+func (l *Logger) SlowQuery(traceId string, query string, elapsed, threshold time.Duration, fields ...Field) {
+	traceId = l.resolveTraceID(traceId)
+	if elapsed <= threshold {
+		return
+	}
+
+	l.log(zapcore.WarnLevel, 1, time.Time{}, traceId, "slow query", nil, append([]Field{
+		String("log_type", "slow_query"),
+		Int64("duration_ms", elapsed.Milliseconds()),
+		Int64("threshold_ms", threshold.Milliseconds()),
+		String("query", normalizeQuery(query)),
+	}, fields...))
+}
+
+// normalizeQuery replaces literals in query with ?, collapses whitespace,
+// and truncates the result to maxSlowQueryLength bytes, marking it with
+// "...".
+func normalizeQuery(query string) string {
+	query = queryLiteral.ReplaceAllStringFunc(query, func(literal string) string {
+		if strings.HasPrefix(literal, "$") {
+			return literal
+		}
+		return "?"
+	})
+	query = strings.TrimSpace(queryWhitespace.ReplaceAllString(query, " "))
+	if len(query) > maxSlowQueryLength {
+		cut := maxSlowQueryLength
+		for cut > 0 && !utf8.RuneStart(query[cut]) { // Don't split a character
+			cut--
+		}
+		query = query[:cut] + "..."
+	}
+	return query
+}
@@ -0,0 +1,79 @@
+package log_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+// steppingClock is a Clock that advances by a fixed step on every call.
+type steppingClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+func TestLogger_TimeThreshold(t *testing.T) {
+	clock := &steppingClock{now: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC), step: 300 * time.Millisecond}
+	logger, entries := newTestLogger(t, log.Config{Clock: clock})
+
+	logger.TimeThreshold("req-fast", "slow query", time.Second)()
+	logger.TimeThreshold("req-slow", "slow query", 100*time.Millisecond)()
+
+	got := entries()
+	if len(got) != 1 {
+		t.Fatalf("expected only the slow operation to be logged, got %d entries", len(got))
+	}
+	logEntry := got[0]
+	if logEntry["level"] != "warn" || logEntry["trace_id"] != "req-slow" || logEntry["message"] != "slow query" {
+		t.Errorf("unexpected entry %v", logEntry)
+	}
+	if logEntry["duration_ms"] != float64(300) || logEntry["threshold_ms"] != float64(100) {
+		t.Errorf("expected duration_ms=300 threshold_ms=100, got %v and %v", logEntry["duration_ms"], logEntry["threshold_ms"])
+	}
+}
+
+func TestLogger_SlowQuery(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	query := "SELECT *\n  FROM orders\n  WHERE user_id = 42 AND note = 'it''s secret' AND id = $1 AND table1.x = 3.5"
+	logger.SlowQuery("req-fast", query, 50*time.Millisecond, 200*time.Millisecond)
+	logger.SlowQuery("req-slow", query, 350*time.Millisecond, 200*time.Millisecond, log.String("db", "orders"))
+
+	got := entries()
+	if len(got) != 1 {
+		t.Fatalf("expected only the slow query to be logged, got %d entries", len(got))
+	}
+	logEntry := got[0]
+	if logEntry["level"] != "warn" || logEntry["message"] != "slow query" || logEntry["log_type"] != "slow_query" || logEntry["db"] != "orders" {
+		t.Errorf("unexpected entry %v", logEntry)
+	}
+	if logEntry["duration_ms"] != float64(350) || logEntry["threshold_ms"] != float64(200) {
+		t.Errorf("expected duration_ms=350 and threshold_ms=200, got %v and %v", logEntry["duration_ms"], logEntry["threshold_ms"])
+	}
+	want := "SELECT * FROM orders WHERE user_id = ? AND note = ? AND id = $1 AND table1.x = ?"
+	if logEntry["query"] != want {
+		t.Errorf("expected normalized query %q, got %q", want, logEntry["query"])
+	}
+}
+
+func TestLogger_SlowQueryTruncated(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	logger.SlowQuery("req-1", "SELECT "+strings.Repeat("column_name, ", 200)+"id FROM t", time.Second, 0)
+	query, _ := entries()[0]["query"].(string)
+	if len(query) != 1024+len("...") || !strings.HasSuffix(query, "...") {
+		t.Errorf("expected the query truncated to 1024 bytes plus a marker, got %d bytes", len(query))
+	}
+}
@@ -0,0 +1,91 @@
+package log
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// stackTruncatedMarker ends a stacktrace that was cut at the configured depth.
+const stackTruncatedMarker = "...truncated"
+
+// callerFrames returns the frames of the calling goroutine's stack, starting
+// skip frames above the caller of callerFrames. If maxDepth is positive, at most
+// maxDepth frames are returned and truncated reports whether any were dropped.
+func callerFrames(skip, maxDepth int) (frames []runtime.Frame, truncated bool) {
+	pcs := make([]uintptr, 64)
+	for {
+		// +2 skips runtime.Callers and callerFrames itself
+		n := runtime.Callers(skip+2, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+
+	iter := runtime.CallersFrames(pcs)
+	for {
+		frame, more := iter.Next()
+		if maxDepth > 0 && len(frames) == maxDepth {
+			return frames, true
+		}
+		frames = append(frames, frame)
+		if !more {
+			return frames, false
+		}
+	}
+}
+
+// formatStack formats frames in the same layout as zap's stacktraces:
+// the function name, then the tab-indented file:line, one frame per pair of lines.
+func formatStack(frames []runtime.Frame, truncated bool) string {
+	var sb strings.Builder
+	for i, frame := range frames {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(frame.Function)
+		sb.WriteString("\n\t")
+		sb.WriteString(frame.File)
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(frame.Line))
+	}
+	if truncated {
+		sb.WriteByte('\n')
+		sb.WriteString(stackTruncatedMarker)
+	}
+	return sb.String()
+}
+
+// Stack creates a field with the call stack at the point Stack is called, as
+// an array of frames, innermost first:
+//
+//	"stack": [{"function": "main.handle", "file": "/app/main.go", "line": 42}, ...]
+//
+// Use it to attach a stack to a chosen entry without enabling stacktraces for
+// a whole level (Config.StacktraceLevel).
+func Stack(key string) Field {
+	frames, _ := callerFrames(1, 0)
+	return Field{zapField: zap.Array(key, stackFrames(frames))}
+}
+
+// stackFrames encodes frames as an array of {function, file, line} objects.
+type stackFrames []runtime.Frame
+
+func (s stackFrames) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, frame := range s {
+		if err := enc.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("function", frame.Function)
+			enc.AddString("file", frame.File)
+			enc.AddInt("line", frame.Line)
+			return nil
+		})); err != nil {
+			return err
+		}
+	}
+	return nil
+}
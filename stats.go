@@ -0,0 +1,44 @@
+package log
+
+// Stats is a snapshot of the logger's delivery counters.
+// Counters are shared between a logger and all of its child loggers.
+type Stats struct {
+	// DroppedEntries is the number of entries discarded because the output
+	// channel was full (OutputChannel without BlockOnFullChannel).
+	DroppedEntries uint64
+
+	// AdaptiveDroppedEntries is the number of debug and info entries dropped
+	// by adaptive sampling while the output was under pressure.
+	AdaptiveDroppedEntries uint64
+
+	// RateLimitedEntries and RateLimitedBytes are the number of entries, and
+	// their total size, dropped by Config.MaxBytesPerSecond.
+	RateLimitedEntries uint64
+	RateLimitedBytes   uint64
+
+	// SamplingDroppedEntries is the number of entries dropped by
+	// Config.Sampling.
+	SamplingDroppedEntries uint64
+
+	// DisallowedFields is the number of fields and metadata keys dropped
+	// because Config.AllowedFieldKeys does not list their key. A field bound
+	// with With is counted once, when it is bound.
+	DisallowedFields uint64
+
+	// SuppressedRepeats is the number of entries suppressed by
+	// Config.RepeatCooldown.
+	SuppressedRepeats uint64
+}
+
+// Stats returns a snapshot of the logger's delivery counters.
+func (l *Logger) Stats() Stats {
+	return Stats{
+		DroppedEntries:         l.stats.DroppedEntries.Load(),
+		AdaptiveDroppedEntries: l.stats.AdaptiveDroppedEntries.Load(),
+		RateLimitedEntries:     l.stats.RateLimitedEntries.Load(),
+		RateLimitedBytes:       l.stats.RateLimitedBytes.Load(),
+		SamplingDroppedEntries: l.stats.SamplingDroppedEntries.Load(),
+		DisallowedFields:       l.stats.DisallowedFields.Load(),
+		SuppressedRepeats:      l.stats.SuppressedRepeats.Load(),
+	}
+}
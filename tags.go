@@ -0,0 +1,35 @@
+package log
+
+import "slices"
+
+// WithTags returns a child logger whose entries include a 'tags' string array
+// with the given tags added to the logger's own. Tags accumulate as an
+// operation proceeds: each call adds to the tags of its parent instead of
+// replacing them, and a tag that is already present is not repeated. Tags are
+// written in the order they were first added.
+//
+// Example:
+//
+//	opLogger := logger.WithTags("retry")
+//	opLogger.WithTags("degraded", "retry").Info(traceID, "fell back to replica", nil)
+//	// "tags": ["retry", "degraded"]
+//
+// Panics if a tag is empty.
+func (l *Logger) WithTags(tags ...string) *Logger {
+	added := l.tags
+	for _, tag := range tags {
+		if tag == "" {
+			panic("log: tag cannot be empty")
+		}
+		if !slices.Contains(added, tag) {
+			added = append(slices.Clip(added), tag) // Never share the array with the parent
+		}
+	}
+	if len(added) == len(l.tags) {
+		return l
+	}
+
+	child := *l
+	child.tags = added
+	return &child
+}
@@ -0,0 +1,52 @@
+package log_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestLogger_WithTags(t *testing.T) {
+	logger, entries := newTestLogger(t, log.Config{})
+
+	retry := logger.WithTags("retry")
+	degraded := retry.WithTags("degraded", "retry")
+	other := retry.WithTags("cached")
+	degraded.With(log.String("replica", "db-2")).Info("req-1", "fell back to replica", nil)
+	other.Info("req-2", "served from cache", nil)
+	retry.Info("req-3", "retrying", nil)
+	logger.Info("req-4", "untagged", nil)
+
+	got := entries()
+	tags := func(logEntry map[string]any) []string {
+		var tags []string
+		for _, tag := range logEntry["tags"].([]any) {
+			tags = append(tags, tag.(string))
+		}
+		return tags
+	}
+	if want := []string{"retry", "degraded"}; !slices.Equal(tags(got[0]), want) || got[0]["replica"] != "db-2" {
+		t.Errorf("expected accumulated, deduplicated tags %v, got %v", want, got[0])
+	}
+	if want := []string{"retry", "cached"}; !slices.Equal(tags(got[1]), want) {
+		t.Errorf("expected sibling tags %v unaffected by other children, got %v", want, got[1]["tags"])
+	}
+	if want := []string{"retry"}; !slices.Equal(tags(got[2]), want) {
+		t.Errorf("expected parent tags %v unchanged, got %v", want, got[2]["tags"])
+	}
+	if _, exists := got[3]["tags"]; exists {
+		t.Errorf("expected no tags on the root logger, got %v", got[3]["tags"])
+	}
+}
+
+func TestLogger_WithTagsEmpty(t *testing.T) {
+	logger, _ := newTestLogger(t, log.Config{})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for empty tag, got none")
+		}
+	}()
+	logger.WithTags("retry", "")
+}
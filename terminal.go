@@ -0,0 +1,41 @@
+package log
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// IsTerminal reports whether output writes to a terminal. Only OutputStdout
+// and OutputStderr can; file, channel, and registered outputs always return
+// false. Use it to choose an encoding before calling New.
+//
+// Example:
+//
+//	cfg.Console = log.IsTerminal(log.OutputStdout)
+func IsTerminal(output OutputType) bool {
+	switch output {
+	case OutputStdout:
+		return IsTerminalWriter(os.Stdout)
+	case OutputStderr:
+		return IsTerminalWriter(os.Stderr)
+	default:
+		return false
+	}
+}
+
+// IsTerminalWriter reports whether w is a terminal, such as os.Stderr when it
+// is not redirected. Writers without a file descriptor return false.
+func IsTerminalWriter(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// consoleWriter returns where console entries are written for cfg.
+func consoleWriter(cfg Config) io.Writer {
+	if cfg.ConsoleWriter != nil {
+		return cfg.ConsoleWriter
+	}
+	return os.Stderr
+}
@@ -0,0 +1,30 @@
+package log_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+func TestIsTerminal(t *testing.T) {
+	for _, output := range []log.OutputType{log.OutputFile, log.OutputChannel, "registered"} {
+		if log.IsTerminal(output) {
+			t.Errorf("expected %s never to be a terminal", output)
+		}
+	}
+
+	if log.IsTerminalWriter(&bytes.Buffer{}) {
+		t.Error("expected a writer without a file descriptor not to be a terminal")
+	}
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	defer file.Close()
+	if log.IsTerminalWriter(file) {
+		t.Error("expected a regular file not to be a terminal")
+	}
+}
@@ -0,0 +1,83 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// AssertNoErrors returns a debug-level logger for use in tests, together with
+// a check function that fails t if any entry at error level or above was
+// logged, printing those entries. Defer the check right after the call:
+//
+//	func TestCheckout(t *testing.T) {
+//	    logger, check := log.AssertNoErrors(t)
+//	    defer check()
+//
+//	    svc := checkout.New(logger)
+//	    // ...
+//	}
+func AssertNoErrors(t testing.TB) (*Logger, func()) {
+	t.Helper()
+
+	sink := &recordingSink{}
+	logger, err := newLogger(Config{
+		Service: "test",
+		Env:     "dev",
+		Level:   DebugLevel,
+		Output:  OutputStdout, // Replaced by sink
+	}, sink, nil)
+	if err != nil {
+		t.Fatalf("log: failed to create test logger: %v", err)
+	}
+
+	check := func() {
+		t.Helper()
+		if err := logger.Sync(); err != nil {
+			t.Errorf("log: failed to flush test logger: %v", err)
+		}
+
+		var errorEntries []string
+		for _, line := range sink.lines() {
+			entry, err := parseEntry(line)
+			if err != nil {
+				t.Errorf("log: unreadable entry %q: %v", line, err)
+				continue
+			}
+			if level, err := entry.Level.toZapLevel(); err == nil && level >= zapcore.ErrorLevel {
+				errorEntries = append(errorEntries, string(line))
+			}
+		}
+		if len(errorEntries) > 0 {
+			t.Errorf("log: %d error-level entries logged:\n%s", len(errorEntries), strings.Join(errorEntries, "\n"))
+		}
+	}
+
+	return logger, check
+}
+
+// recordingSink is a WriteSyncer that keeps a copy of every entry.
+type recordingSink struct {
+	mu      sync.Mutex
+	entries [][]byte
+}
+
+func (s *recordingSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, bytes.TrimSpace(bytes.Clone(p)))
+	return len(p), nil
+}
+
+func (s *recordingSink) Sync() error {
+	return nil
+}
+
+func (s *recordingSink) lines() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries
+}
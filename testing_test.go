@@ -0,0 +1,46 @@
+package log_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/glennprays/log"
+)
+
+// recordingTB captures failures reported through testing.TB.
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertNoErrors_Passes(t *testing.T) {
+	logger, check := log.AssertNoErrors(t)
+	defer check()
+
+	logger.Debug("req-1", "debug", nil)
+	logger.Info("req-2", "info", nil)
+	logger.Warn("req-3", "warn", nil)
+}
+
+func TestAssertNoErrors_FailsOnErrorEntries(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	logger, check := log.AssertNoErrors(tb)
+
+	logger.Info("req-1", "fine", nil)
+	logger.Error("req-2", "payment failed", nil)
+	check()
+
+	if len(tb.failures) != 1 {
+		t.Fatalf("expected 1 failure, got %d: %v", len(tb.failures), tb.failures)
+	}
+	if !strings.Contains(tb.failures[0], "1 error-level entries logged") || !strings.Contains(tb.failures[0], "payment failed") {
+		t.Errorf("expected failure to print the error entry, got %q", tb.failures[0])
+	}
+}
@@ -0,0 +1,38 @@
+package log
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Trace logs the start and end of an operation around a call to fn and
+// returns fn's error. It logs "<op> started" at info level, then either
+// "<op> completed" at info level or "<op> failed" at error level with the
+// error attached. Every entry has an 'op' field, and the end entry a
+// 'duration_ms' field. Time is read from Config.Clock.
+//
+// Example:
+//
+//	err := log.Trace(logger, traceId, "charge card", func() error {
+//	    return payments.Charge(ctx, card, amount)
+//	})
+//
+// Panics if traceId is empty and the logger has no default (WithDefaultTraceID).
+func Trace(logger *Logger, traceId string, op string, fn func() error) error {
+	traceId = logger.resolveTraceID(traceId)
+
+	opField := String("op", op)
+	logger.log(zapcore.InfoLevel, 1, time.Time{}, traceId, op+" started", nil, []Field{opField})
+
+	start := logger.clock.Now()
+	err := fn()
+	duration := Int64("duration_ms", logger.clock.Now().Sub(start).Milliseconds())
+
+	if err != nil {
+		logger.log(zapcore.ErrorLevel, 1, time.Time{}, traceId, op+" failed", nil, []Field{opField, duration, Error(err)})
+		return err
+	}
+	logger.log(zapcore.InfoLevel, 1, time.Time{}, traceId, op+" completed", nil, []Field{opField, duration})
+	return nil
+}
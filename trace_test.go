@@ -0,0 +1,40 @@
+package log_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glennprays/log"
+)
+
+func TestTrace(t *testing.T) {
+	clock := &steppingClock{now: time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC), step: 300 * time.Millisecond}
+	logger, entries := newTestLogger(t, log.Config{Clock: clock})
+
+	if err := log.Trace(logger, "req-1", "charge card", func() error { return nil }); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	got := entries()
+	if len(got) != 2 {
+		t.Fatalf("expected start and end entries, got %d", len(got))
+	}
+	if got[0]["message"] != "charge card started" || got[0]["level"] != "info" || got[0]["op"] != "charge card" {
+		t.Errorf("unexpected start entry %v", got[0])
+	}
+	if _, exists := got[0]["duration_ms"]; exists {
+		t.Errorf("expected no duration on the start entry, got %v", got[0]["duration_ms"])
+	}
+	if got[1]["message"] != "charge card completed" || got[1]["level"] != "info" || got[1]["duration_ms"] != float64(300) {
+		t.Errorf("unexpected end entry %v", got[1])
+	}
+
+	wantErr := errors.New("card declined")
+	if err := log.Trace(logger, "req-2", "charge card", func() error { return wantErr }); err != wantErr {
+		t.Fatalf("expected fn's error, got %v", err)
+	}
+	got = entries()
+	if got[1]["message"] != "charge card failed" || got[1]["level"] != "error" || got[1]["error"] != "card declined" {
+		t.Errorf("unexpected failure entry %v", got[1])
+	}
+}